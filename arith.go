@@ -0,0 +1,196 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"time"
+)
+
+// AddDays returns a new YMDFlag `n` days after the YMDFlag's date, with the same location.
+// Negative `n` goes backward.  A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) AddDays(n int) YMDFlag {
+	return ymd.AddDate(0, 0, n)
+}
+
+// NextDay returns a new YMDFlag for the following calendar day, with the same location.
+// It is the same as `AddDays(1)`, rolling over month and year boundaries.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) NextDay() YMDFlag {
+	return ymd.AddDays(1)
+}
+
+// PrevDay returns a new YMDFlag for the preceding calendar day, with the same location.
+// It is the same as `AddDays(-1)`, rolling over month and year boundaries.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) PrevDay() YMDFlag {
+	return ymd.AddDays(-1)
+}
+
+// AddMonths returns a new YMDFlag `n` months after the YMDFlag's date, with the same location.
+// Negative `n` goes backward.  Like `time.Time.AddDate`, overflowing days roll into the following month,
+// so October 31 plus one month is December 1.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) AddMonths(n int) YMDFlag {
+	return ymd.AddDate(0, n, 0)
+}
+
+// AddYears returns a new YMDFlag `n` years after the YMDFlag's date, with the same location.
+// Negative `n` goes backward.  Like `time.Time.AddDate`, February 29 plus one year is March 1.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) AddYears(n int) YMDFlag {
+	return ymd.AddDate(n, 0, 0)
+}
+
+// AddDate returns a new YMDFlag offset by the given years, months, and days, with the same location.
+// It normalizes exactly as `time.Time.AddDate` does, so October 31 plus one month is December 1.
+// The offset is computed on the calendar date in UTC, so DST transitions in the location have no effect.
+// Results outside MinYear through MaxYear saturate to 00010101 or 99991231, so AddDate never produces an invalid date.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) AddDate(years, months, days int) YMDFlag {
+	ymd = ymd.resolved()
+	ymd.yyyymmdd = saturatedYMD(YMDToTime(ymd.yyyymmdd, time.UTC).AddDate(years, months, days))
+	return ymd
+}
+
+// AddDuration returns a new YMDFlag offset by `d` rounded to the nearest whole number of 24-hour days,
+// with the same location.  Halfway values round away from zero, so 36h adds 2 days and -12h subtracts 1 day,
+// while anything less than 12h in magnitude leaves the date unchanged.
+// The duration is converted to calendar days rather than added to the wall clock, so DST transitions in the
+// location have no effect: 24h from the midnight before a 25-hour fall-back day is still the next date.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) AddDuration(d time.Duration) YMDFlag {
+	const day = 24 * time.Hour
+	return ymd.AddDate(0, 0, int(d.Round(day)/day))
+}
+
+// WithDay returns a new YMDFlag with the same year, month, and location, but with the day-of-month `day`.
+// Returns a non-nil error if the month has no such day.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) WithDay(day int) (YMDFlag, error) {
+	ymd = ymd.resolved()
+	year, month, _ := ymd.AsYearMonthDay()
+	if day < 1 || day > daysInMonth(year, month) {
+		return YMDFlag{}, fmt.Errorf("day %d is invalid for %04d-%02d", day, year, month)
+	}
+	ymd.yyyymmdd = 10000*year + 100*month + day
+	return ymd, nil
+}
+
+// WithMonth returns a new YMDFlag with the same year, day-of-month, and location, but in `month`.
+// Returns a non-nil error if `month` is invalid or lacks the day, such as moving the 31st to February.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) WithMonth(month time.Month) (YMDFlag, error) {
+	ymd = ymd.resolved()
+	year, _, day := ymd.AsYearMonthDay()
+	if month < time.January || month > time.December {
+		return YMDFlag{}, fmt.Errorf("month %d is invalid", month)
+	}
+	if day > daysInMonth(year, int(month)) {
+		return YMDFlag{}, fmt.Errorf("day %d is invalid for %04d-%02d", day, year, month)
+	}
+	ymd.yyyymmdd = 10000*year + 100*int(month) + day
+	return ymd, nil
+}
+
+// WithYear returns a new YMDFlag with the same month, day-of-month, and location, but in `year`.
+// Returns a non-nil error if `year` is not representable as `YYYY`,
+// or if the date is February 29 and `year` is not a leap year.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+func (ymd YMDFlag) WithYear(year int) (YMDFlag, error) {
+	ymd = ymd.resolved()
+	_, month, day := ymd.AsYearMonthDay()
+	if year < MinYear || year > MaxYear {
+		return YMDFlag{}, fmt.Errorf("year %d is invalid", year)
+	}
+	if month == 2 && day == 29 && !IsLeapYear(year) {
+		return YMDFlag{}, fmt.Errorf("%04d is not a leap year", year)
+	}
+	ymd.yyyymmdd = 10000*year + 100*month + day
+	return ymd, nil
+}
+
+// Sub returns the signed number of calendar days from `other` to the YMDFlag's date,
+// which is positive when the YMDFlag is after `other`, like `time.Time.Sub`.
+// The dates are subtracted at midnight UTC, so DST transitions in either location never cause an off-by-one.
+// Nil YMDFlags are resolved to today, without mutating them.
+func (ymd YMDFlag) Sub(other YMDFlag) int {
+	return daysSinceEpoch(ymd.resolved().yyyymmdd) - daysSinceEpoch(other.resolved().yyyymmdd)
+}
+
+// DaysBetween returns the signed number of calendar days from `a` to `b`, which is `b.Sub(a)`.
+func DaysBetween(a, b YMDFlag) int {
+	return b.Sub(a)
+}
+
+// CivilDaysBetween returns the signed number of calendar days from `a` to `b`, like DaysBetween,
+// but computed purely from the `YYYYMMDD` components without constructing a `time.Time`,
+// so neither location nor DST are involved.  Nil YMDFlags are resolved to today, without mutating them.
+func CivilDaysBetween(a, b YMDFlag) int {
+	return daysFromCivil(b.resolved().yyyymmdd) - daysFromCivil(a.resolved().yyyymmdd)
+}
+
+// daysFromCivil returns the number of days from 1970-01-01 to the proleptic Gregorian `yyyymmdd`,
+// using Howard Hinnant's days_from_civil algorithm.
+func daysFromCivil(yyyymmdd int) int {
+	year, month, day := yyyymmdd/10000, yyyymmdd/100%100, yyyymmdd%100
+	if month <= 2 {
+		year-- // years begin on March 1, so the leap day is last
+	}
+	era := year / 400
+	if year < 0 {
+		era = (year - 399) / 400
+	}
+	yearOfEra := year - era*400                                         // [0, 399]
+	monthFromMarch := (month + 9) % 12                                  // [0, 11]
+	dayOfYear := (153*monthFromMarch+2)/5 + day - 1                     // [0, 365]
+	dayOfEra := yearOfEra*365 + yearOfEra/4 - yearOfEra/100 + dayOfYear // [0, 146096]
+	return era*146097 + dayOfEra - 719468
+}
+
+// DiffIn returns the signed whole number of `unit`s from `other` to the YMDFlag's date,
+// which is positive when the YMDFlag is after `other`.  Partial units are truncated toward zero.
+// The supported units are "days", "weeks", "months", and "years"; a month or year is only counted
+// once its day-of-month anniversary is reached.  DiffIn panics for any other unit.
+// Nil YMDFlags are resolved to today, without mutating them.
+func (ymd YMDFlag) DiffIn(other YMDFlag, unit string) int {
+	from, to := other.resolved().yyyymmdd, ymd.resolved().yyyymmdd
+	switch unit {
+	case "days":
+		return ymd.Sub(other)
+	case "weeks":
+		return ymd.Sub(other) / 7
+	case "months":
+		return wholeMonthsBetween(from, to)
+	case "years":
+		return wholeMonthsBetween(from, to) / 12
+	default:
+		panic(fmt.Sprintf("ymdflag: unknown DiffIn unit %q", unit))
+	}
+}
+
+// YearsBetween returns the signed number of whole years from `a` to `b`, such as an age.
+// A year is only counted once its anniversary is reached, so a February 29 anniversary is reached on March 1
+// in non-leap years.  Partial years are truncated toward zero.  This is `b.DiffIn(a, "years")`.
+// Nil YMDFlags are resolved to today, without mutating them.
+func YearsBetween(a, b YMDFlag) int {
+	return b.DiffIn(a, "years")
+}
+
+// YearsSince returns the number of whole years from the YMDFlag's date until today in its location, such as an age.
+// It is negative for future dates.  See YearsBetween for details.  Today is determined using NowFunc.
+func (ymd YMDFlag) YearsSince() int {
+	return YearsBetween(ymd, NewYMDFlagToday(ymd.loc))
+}
+
+// wholeMonthsBetween returns the signed number of whole months from the `from` yyyymmdd to the `to` yyyymmdd.
+func wholeMonthsBetween(from, to int) int {
+	if to < from {
+		return -wholeMonthsBetween(to, from)
+	}
+	months := (to/10000-from/10000)*12 + (to/100%100 - from/100%100)
+	if to%100 < from%100 {
+		months-- // anniversary day not yet reached
+	}
+	return months
+}
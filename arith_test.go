@@ -0,0 +1,276 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDaysMonthsYears(t *testing.T) {
+	loc := time.FixedZone("UTC-8", -8*60*60)
+	ymdFlag := mustYMDIn(t, 20230131, loc)
+
+	assert.Equal(t, 20230201, ymdFlag.AddDays(1).GetYMD(), "month boundary")
+	assert.Equal(t, 20230130, ymdFlag.AddDays(-1).GetYMD(), "negative step")
+	assert.Equal(t, 20221231, ymdFlag.AddDays(-31).GetYMD(), "year boundary backward")
+	assert.Equal(t, 20230131, ymdFlag.AddDays(0).GetYMD(), "zero step")
+	assert.Equal(t, loc, ymdFlag.AddDays(1).Location(), "location is preserved")
+	assert.Equal(t, 20230131, ymdFlag.GetYMD(), "receiver is unchanged")
+
+	assert.Equal(t, 20230303, ymdFlag.AddMonths(1).GetYMD(), "Jan 31 + 1 month rolls over Feb 28")
+	assert.Equal(t, 20221231, ymdFlag.AddMonths(-1).GetYMD(), "negative months")
+	assert.Equal(t, 20240131, ymdFlag.AddMonths(12).GetYMD(), "twelve months")
+
+	leapDay := mustYMD(t, 20240229)
+	assert.Equal(t, 20250301, leapDay.AddYears(1).GetYMD(), "leap day + 1 year")
+	assert.Equal(t, 20280229, leapDay.AddYears(4).GetYMD(), "leap day + 4 years")
+	assert.Equal(t, 20230301, leapDay.AddYears(-1).GetYMD(), "leap day - 1 year")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, 20230705, zero.AddDays(1).GetYMD(), "nil resolves to today first")
+	assert.True(t, zero.IsZero(), "nil receiver is unchanged")
+}
+
+func TestNextPrevDay(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+	endOfJan := mustYMDIn(t, 20240131, loc)
+	assert.Equal(t, 20240201, endOfJan.NextDay().GetYMD(), "month boundary")
+	assert.Equal(t, 20240131, endOfJan.NextDay().PrevDay().GetYMD(), "month boundary backward")
+	assert.Equal(t, loc, endOfJan.NextDay().Location(), "location is preserved")
+	assert.Equal(t, loc, endOfJan.PrevDay().Location(), "location is preserved")
+
+	assert.Equal(t, 20240301, mustYMD(t, 20240229).NextDay().GetYMD(), "leap day")
+	assert.Equal(t, 20240229, mustYMD(t, 20240301).PrevDay().GetYMD(), "leap day backward")
+
+	newYearsEve := mustYMD(t, 20231231)
+	assert.Equal(t, 20240101, newYearsEve.NextDay().GetYMD(), "year boundary")
+	assert.Equal(t, 20231231, mustYMD(t, 20240101).PrevDay().GetYMD(), "year boundary backward")
+	assert.Equal(t, 20231231, newYearsEve.GetYMD(), "receiver is unchanged")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, 20230705, zero.NextDay().GetYMD(), "nil resolves to today first")
+	assert.Equal(t, 20230703, zero.PrevDay().GetYMD(), "nil resolves to today first")
+	assert.True(t, zero.IsZero(), "nil receiver is unchanged")
+}
+
+func TestAddDate(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ymdFlag := mustYMDIn(t, 20231031, tokyo)
+	assert.Equal(t, 20231201, ymdFlag.AddDate(0, 1, 0).GetYMD(), "October 31 plus one month normalizes to December 1")
+	assert.Equal(t, tokyo, ymdFlag.AddDate(0, 1, 0).Location(), "location is preserved")
+
+	// from the time package's AddDate example
+	start := mustYMD(t, 20230101)
+	assert.Equal(t, 20230102, start.AddDate(0, 0, 1).GetYMD())
+	assert.Equal(t, 20230201, start.AddDate(0, 1, 0).GetYMD())
+	assert.Equal(t, 20240101, start.AddDate(1, 0, 0).GetYMD())
+
+	assert.Equal(t, 20250301, mustYMD(t, 20240229).AddDate(1, 0, 0).GetYMD(), "February 29 plus one year")
+	assert.Equal(t, 20221231, start.AddDate(0, 0, -1).GetYMD(), "negative")
+	assert.Equal(t, 20241130, mustYMD(t, 20230930).AddDate(1, 2, 0).GetYMD(), "combined")
+
+	for _, yyyymmdd := range []int{20231031, 20240229, 20230101} {
+		ymdFlag := mustYMD(t, yyyymmdd)
+		expected := TimeToYMD(ymdFlag.AsTimeRawWithLoc(time.UTC).AddDate(1, -3, 45))
+		assert.Equal(t, expected, ymdFlag.AddDate(1, -3, 45).GetYMD(), "same as time.Time.AddDate for %d", yyyymmdd)
+	}
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, 20230805, zero.AddDate(0, 1, 1).GetYMD(), "nil resolves to today first")
+	assert.True(t, zero.IsZero())
+}
+
+func TestAddDuration(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ymdFlag := mustYMDIn(t, 20230704, tokyo)
+	assert.Equal(t, 20230706, ymdFlag.AddDuration(48*time.Hour).GetYMD())
+	assert.Equal(t, 20230706, ymdFlag.AddDuration(36*time.Hour).GetYMD(), "halfway rounds away from zero")
+	assert.Equal(t, 20230705, ymdFlag.AddDuration(35*time.Hour).GetYMD(), "rounds down")
+	assert.Equal(t, 20230704, ymdFlag.AddDuration(11*time.Hour).GetYMD(), "less than half a day")
+	assert.Equal(t, 20230702, ymdFlag.AddDuration(-48*time.Hour).GetYMD(), "negative")
+	assert.Equal(t, 20230702, ymdFlag.AddDuration(-36*time.Hour).GetYMD(), "negative halfway rounds away from zero")
+	assert.Equal(t, 20230703, ymdFlag.AddDuration(-12*time.Hour).GetYMD())
+	assert.Equal(t, tokyo, ymdFlag.AddDuration(24*time.Hour).Location(), "location is preserved")
+
+	newYork := mustLoadLocation(t, "America/New_York")
+	fallBack := mustYMDIn(t, 20231105, newYork)
+	assert.Equal(t, 20231106, fallBack.AddDuration(24*time.Hour).GetYMD(), "calendar days across a 25-hour day")
+	assert.Equal(t, 5, fallBack.AsTime().Add(24*time.Hour).Day(), "wall clock would stay on the same date")
+	springForward := mustYMDIn(t, 20230312, newYork)
+	assert.Equal(t, 20230313, springForward.AddDuration(23*time.Hour).GetYMD(), "23h rounds to one calendar day")
+	assert.Equal(t, 20230311, springForward.AddDuration(-24*time.Hour).GetYMD())
+}
+
+func TestWithDay(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ymdFlag, err := mustYMDIn(t, 20230704, tokyo).WithDay(31)
+	assert.NoError(t, err)
+	assert.Equal(t, 20230731, ymdFlag.GetYMD())
+	assert.Equal(t, tokyo, ymdFlag.Location(), "location is preserved")
+
+	ymdFlag, err = mustYMD(t, 20240201).WithDay(29)
+	assert.NoError(t, err, "leap day")
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+
+	for _, day := range []int{0, -1, 30, 100} {
+		_, err = mustYMD(t, 20240201).WithDay(day)
+		assert.Error(t, err, "day %d in February", day)
+	}
+	_, err = mustYMD(t, 20230201).WithDay(29)
+	assert.Error(t, err, "not a leap year")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	ymdFlag, err = zero.WithDay(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20230701, ymdFlag.GetYMD(), "nil resolves to today first")
+	assert.True(t, zero.IsZero())
+}
+
+func TestWithMonth(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ymdFlag, err := mustYMDIn(t, 20230731, tokyo).WithMonth(time.December)
+	assert.NoError(t, err)
+	assert.Equal(t, 20231231, ymdFlag.GetYMD())
+	assert.Equal(t, tokyo, ymdFlag.Location(), "location is preserved")
+
+	ymdFlag, err = mustYMD(t, 20240129).WithMonth(time.February)
+	assert.NoError(t, err, "leap day")
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+
+	_, err = mustYMD(t, 20230131).WithMonth(time.February)
+	assert.Error(t, err, "no February 31")
+	_, err = mustYMD(t, 20230331).WithMonth(time.April)
+	assert.Error(t, err, "no April 31")
+	_, err = mustYMD(t, 20230129).WithMonth(time.February)
+	assert.Error(t, err, "not a leap year")
+	_, err = mustYMD(t, 20230101).WithMonth(13)
+	assert.Error(t, err, "invalid month")
+	_, err = mustYMD(t, 20230101).WithMonth(0)
+	assert.Error(t, err, "invalid month")
+}
+
+func TestWithYear(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ymdFlag, err := mustYMDIn(t, 20230704, tokyo).WithYear(1999)
+	assert.NoError(t, err)
+	assert.Equal(t, 19990704, ymdFlag.GetYMD())
+	assert.Equal(t, tokyo, ymdFlag.Location(), "location is preserved")
+
+	ymdFlag, err = mustYMD(t, 20240229).WithYear(2000)
+	assert.NoError(t, err, "leap day to a leap year")
+	assert.Equal(t, 20000229, ymdFlag.GetYMD())
+
+	_, err = mustYMD(t, 20240229).WithYear(2023)
+	assert.Error(t, err, "leap day to a non-leap year")
+	_, err = mustYMD(t, 20240229).WithYear(1900)
+	assert.Error(t, err, "leap day to a century non-leap year")
+	_, err = mustYMD(t, 20230704).WithYear(10000)
+	assert.Error(t, err, "more than 4 digits")
+	_, err = mustYMD(t, 20230704).WithYear(-1)
+	assert.Error(t, err, "negative year")
+	_, err = mustYMD(t, 20230704).WithYear(0)
+	assert.Error(t, err, "year 0000")
+	ymdFlag, err = mustYMD(t, 20230704).WithYear(1)
+	assert.NoError(t, err, "year 0001")
+	assert.Equal(t, 10704, ymdFlag.GetYMD())
+}
+
+func TestDiffIn(t *testing.T) {
+	from, to := mustYMD(t, 20210315), mustYMD(t, 20230704)
+	assert.Equal(t, 841, to.DiffIn(from, "days"))
+	assert.Equal(t, 120, to.DiffIn(from, "weeks"))
+	assert.Equal(t, 27, to.DiffIn(from, "months"))
+	assert.Equal(t, 2, to.DiffIn(from, "years"))
+
+	assert.Equal(t, -841, from.DiffIn(to, "days"), "negative")
+	assert.Equal(t, -120, from.DiffIn(to, "weeks"), "negative")
+	assert.Equal(t, -27, from.DiffIn(to, "months"), "negative")
+	assert.Equal(t, -2, from.DiffIn(to, "years"), "negative")
+
+	assert.Equal(t, 0, mustYMD(t, 20230214).DiffIn(mustYMD(t, 20230115), "months"), "anniversary not reached")
+	assert.Equal(t, 1, mustYMD(t, 20230215).DiffIn(mustYMD(t, 20230115), "months"), "anniversary reached")
+	assert.Equal(t, 0, to.DiffIn(to, "days"), "same date")
+
+	assert.Panics(t, func() { to.DiffIn(from, "fortnights") }, "unknown unit")
+}
+
+func TestSub(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	// DST began in New York on Sun Mar 12 2023, so that day is only 23 hours long
+	before, after := mustYMDIn(t, 20230311, newYork), mustYMDIn(t, 20230313, newYork)
+	assert.Equal(t, 47*time.Hour, after.AsTime().Sub(before.AsTime()), "wall clock span is short")
+	assert.Equal(t, 2, after.Sub(before), "spring forward")
+	assert.Equal(t, -2, before.Sub(after), "spring forward, negative")
+	assert.Equal(t, 2, DaysBetween(before, after))
+	assert.Equal(t, -2, DaysBetween(after, before))
+
+	// DST ended on Sun Nov 5 2023, so that day is 25 hours long
+	assert.Equal(t, 1, mustYMDIn(t, 20231106, newYork).Sub(mustYMDIn(t, 20231105, newYork)), "fall back")
+
+	assert.Equal(t, 0, before.Sub(before), "same date")
+	assert.Equal(t, 366, mustYMD(t, 20250101).Sub(mustYMD(t, 20240101)), "leap year")
+	assert.Equal(t, 0, mustYMDIn(t, 20230704, time.UTC).Sub(mustYMDIn(t, 20230704, newYork)), "locations are ignored")
+}
+
+func TestCivilDaysBetween(t *testing.T) {
+	pairs := [][2]int{
+		{20230704, 20230704},
+		{20230704, 20230705},
+		{20230705, 20230704},
+		{20230101, 20240101},
+		{20240101, 20250101},
+		{20240228, 20240301},
+		{19700101, 20230704},
+		{18991231, 21000301},
+		{20000229, 19000228},
+		{10101, 99991231},
+		{20231105, 20231106}, // DST ends in the Americas
+	}
+	for _, pair := range pairs {
+		a, b := mustYMD(t, pair[0]), mustYMD(t, pair[1])
+		// check against time arithmetic in UTC, independently of the epoch-day algorithms
+		expected := int(b.AsTimeRawWithLoc(time.UTC).Sub(a.AsTimeRawWithLoc(time.UTC)).Hours() / 24)
+		if pair[0] == 10101 {
+			// time.Duration saturates at about 292 years, so span the whole range in Unix seconds
+			expected = int((b.AsTimeRawWithLoc(time.UTC).Unix() - a.AsTimeRawWithLoc(time.UTC).Unix()) / (24 * 60 * 60))
+		}
+		assert.Equal(t, expected, CivilDaysBetween(a, b), "%d to %d", pair[0], pair[1])
+	}
+	assert.Equal(t, 0, daysFromCivil(19700101), "epoch")
+	assert.Equal(t, 366, CivilDaysBetween(mustYMD(t, 20240101), mustYMD(t, 20250101)), "leap year")
+}
+
+func TestYearsBetween(t *testing.T) {
+	birth := mustYMD(t, 19900705)
+	assert.Equal(t, 32, YearsBetween(birth, mustYMD(t, 20230704)), "anniversary is tomorrow")
+	assert.Equal(t, 33, YearsBetween(birth, mustYMD(t, 20230705)), "anniversary is today")
+	assert.Equal(t, 33, YearsBetween(birth, mustYMD(t, 20230706)), "anniversary was yesterday")
+	assert.Equal(t, -32, YearsBetween(mustYMD(t, 20230704), birth), "negative")
+	assert.Equal(t, 0, YearsBetween(birth, birth))
+
+	leap := mustYMD(t, 20000229)
+	assert.Equal(t, 22, YearsBetween(leap, mustYMD(t, 20230228)), "leap day anniversary not yet reached")
+	assert.Equal(t, 23, YearsBetween(leap, mustYMD(t, 20230301)))
+	assert.Equal(t, 24, YearsBetween(leap, mustYMD(t, 20240229)))
+}
+
+func TestYearsSince(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 32, mustYMDIn(t, 19900705, time.UTC).YearsSince(), "anniversary is tomorrow")
+	assert.Equal(t, 33, mustYMDIn(t, 19900703, time.UTC).YearsSince(), "anniversary was yesterday")
+	assert.Equal(t, 33, mustYMDIn(t, 19900704, time.UTC).YearsSince(), "anniversary is today")
+	assert.Equal(t, -1, mustYMDIn(t, 20240704, time.UTC).YearsSince(), "future")
+	assert.Equal(t, 0, mustYMDIn(t, 0, time.UTC).YearsSince(), "nil is today")
+
+	// it is already Jul 5 in UTC+14
+	assert.Equal(t, 33, mustYMDIn(t, 19900705, time.FixedZone("UTC+14", 14*60*60)).YearsSince(), "today in the flag's location")
+}
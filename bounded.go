@@ -0,0 +1,59 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+)
+
+// BoundedYMDFlag is a YMDFlag whose Set rejects dates outside of an inclusive window, such as `--date`
+// values which may not be before 2000-01-01 nor after today.
+// It implements the flag.Value and pflag.Value interfaces, and all YMDFlag methods are promoted.
+type BoundedYMDFlag struct {
+	YMDFlag
+	min, max YMDFlag
+}
+
+// NewBoundedYMDFlag creates a new nil BoundedYMDFlag in local time, accepting dates from `min` through `max` inclusive.
+// A nil `min` means there is no lower bound, and a nil `max` means today, resolved when Set is called.
+// Returns a non-nil error wrapping ErrOutOfRange if both bounds are set and `min` is after `max`,
+// since no date could satisfy them.
+func NewBoundedYMDFlag(min, max YMDFlag) (*BoundedYMDFlag, error) {
+	if !min.IsZero() && !max.IsZero() && min.After(max) {
+		return nil, fmt.Errorf("minimum %s is after the maximum %s: %w", min.AsYMDString(), max.AsYMDString(), ErrOutOfRange)
+	}
+	return &BoundedYMDFlag{min: min, max: max}, nil
+}
+
+// Set implements the flag.Value interface, parsing `value` as YMDFlag.Set does and then checking it against the bounds.
+// An empty `value` is today, which must also be within the bounds.
+// Out of bounds dates return a non-nil error wrapping ErrOutOfRange.
+// If the date is invalid or out of bounds, the flag is unchanged.
+func (b *BoundedYMDFlag) Set(value string) error {
+	parsed := b.YMDFlag
+	if err := parsed.Set(value); err != nil {
+		return err
+	}
+	date := parsed.resolved()
+	if !b.min.IsZero() && date.Before(b.min) {
+		return fmt.Errorf("date %s is before the minimum %s: %w", date.AsYMDString(), b.min.AsYMDString(), ErrOutOfRange)
+	}
+	if max := b.maxOrToday(); date.After(max) {
+		return fmt.Errorf("date %s is after the maximum %s: %w", date.AsYMDString(), max.AsYMDString(), ErrOutOfRange)
+	}
+	b.YMDFlag = parsed
+	return nil
+}
+
+// Bounds returns the inclusive minimum and maximum dates, as given to NewBoundedYMDFlag.
+func (b *BoundedYMDFlag) Bounds() (min, max YMDFlag) {
+	return b.min, b.max
+}
+
+// maxOrToday returns the maximum date, or today in the flag's location if it is nil.
+func (b *BoundedYMDFlag) maxOrToday() YMDFlag {
+	if b.max.IsZero() {
+		return NewYMDFlagToday(b.loc)
+	}
+	return b.max
+}
@@ -0,0 +1,68 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustBounded(t *testing.T, min, max YMDFlag) *BoundedYMDFlag {
+	t.Helper()
+	bounded, err := NewBoundedYMDFlag(min, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bounded
+}
+
+func TestNewBoundedYMDFlag(t *testing.T) {
+	_, err := NewBoundedYMDFlag(mustYMD(t, 20231231), mustYMD(t, 20230101))
+	assert.ErrorIs(t, err, ErrOutOfRange, "min after max")
+	_, err = NewBoundedYMDFlag(mustYMD(t, 20230704), mustYMD(t, 20230704))
+	assert.NoError(t, err, "single day")
+	_, err = NewBoundedYMDFlag(mustYMD(t, 20991231), YMDFlag{})
+	assert.NoError(t, err, "nil max is resolved when Set is called")
+}
+
+func TestBoundedYMDFlag(t *testing.T) {
+	bounded := mustBounded(t, mustYMD(t, 20000101), mustYMD(t, 20231231))
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.VarP(bounded, "date", "d", "YYYYMMDD date")
+	assert.NoError(t, fs.Parse([]string{"--date", "20230704"}), "in range")
+	assert.Equal(t, 20230704, bounded.GetYMD())
+	assert.Equal(t, "20230704", bounded.String())
+
+	assert.NoError(t, bounded.Set("20000101"), "at min")
+	assert.NoError(t, bounded.Set("20231231"), "at max")
+	assert.ErrorContains(t, bounded.Set("19991231"), "before the minimum 20000101")
+	assert.ErrorContains(t, bounded.Set("20240101"), "after the maximum 20231231")
+	assert.ErrorIs(t, bounded.Set("19991231"), ErrOutOfRange)
+	assert.ErrorIs(t, bounded.Set("20240101"), ErrOutOfRange)
+	assert.Error(t, bounded.Set("20230230"), "invalid date")
+	assert.Equal(t, 20231231, bounded.GetYMD(), "unchanged on error")
+
+	min, max := bounded.Bounds()
+	assert.Equal(t, 20000101, min.GetYMD())
+	assert.Equal(t, 20231231, max.GetYMD())
+}
+
+func TestBoundedYMDFlagToday(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	bounded := mustBounded(t, YMDFlag{}, YMDFlag{})
+	bounded.SetLocation(time.UTC)
+	assert.NoError(t, bounded.Set("19000101"), "no minimum")
+	assert.NoError(t, bounded.Set("20230704"), "nil max is today")
+	assert.ErrorContains(t, bounded.Set("20230705"), "after the maximum 20230704")
+	assert.ErrorContains(t, bounded.Set("tomorrow"), "after the maximum 20230704")
+	assert.NoError(t, bounded.Set(""), "empty is today")
+	assert.True(t, bounded.IsZero())
+
+	bounded = mustBounded(t, mustYMD(t, 20230801), YMDFlag{})
+	bounded.SetLocation(time.UTC)
+	assert.Error(t, bounded.Set(""), "today is before the minimum")
+}
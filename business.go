@@ -0,0 +1,255 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"time"
+)
+
+// HolidayCalendar reports which dates are holidays for business-day calculations.
+//
+// Business-day methods accept a nil HolidayCalendar, in which case only weekends are skipped.
+type HolidayCalendar interface {
+	// IsHoliday returns true if the YMDFlag's date is a holiday.
+	IsHoliday(ymd YMDFlag) bool
+}
+
+// HolidayFunc adapts an ordinary function to the HolidayCalendar interface.
+type HolidayFunc func(ymd YMDFlag) bool
+
+// IsHoliday implements HolidayCalendar by calling the function.
+func (fn HolidayFunc) IsHoliday(ymd YMDFlag) bool {
+	return fn(ymd)
+}
+
+// HolidaySet is a HolidayCalendar of fixed dates, keyed by their integral `yyyymmdd`.
+// Build it once, for example from a list of market holidays, and reuse it across calculations.
+// It is an alias of YMDSet, so the two are interchangeable.  Membership ignores location.
+type HolidaySet = YMDSet
+
+// NewHolidaySet creates a new HolidaySet of the given dates.  Nil YMDFlags are ignored.
+// It is the same as NewYMDSet.
+func NewHolidaySet(holidays ...YMDFlag) HolidaySet {
+	return NewYMDSet(holidays...)
+}
+
+// IsBusinessDay returns true if the YMDFlag's date is a Monday through Friday and not a holiday in `cal`.
+// A nil `cal` has no holidays.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsBusinessDay(cal HolidayCalendar) bool {
+	ymd = ymd.resolved()
+	if ymd.IsWeekend() {
+		return false
+	}
+	return cal == nil || !cal.IsHoliday(ymd)
+}
+
+// AddBusinessDays returns a new YMDFlag `n` business days (Monday through Friday) after the YMDFlag's date,
+// with the same location.  Negative `n` goes backward.
+// Each step moves to the next business day in that direction, so Saturday plus 1 is Monday,
+// and an `n` of 0 returns the date unchanged, even on a weekend.
+// A nil YMDFlag is resolved to today first, without mutating the receiver.
+// It is AddBusinessDaysCal without holidays.
+func (ymd YMDFlag) AddBusinessDays(n int) YMDFlag {
+	return ymd.AddBusinessDaysCal(n, nil)
+}
+
+// AddBusinessDaysCal is AddBusinessDays, but also skipping the holidays in `holidays`, such as a HolidaySet.
+// A nil `holidays` has no holidays.  If a step finds no business day within maxNonBusinessDays,
+// as with a calendar reporting every day as a holiday, the resolved date is returned unchanged.
+func (ymd YMDFlag) AddBusinessDaysCal(n int, holidays HolidayCalendar) YMDFlag {
+	start := ymd.resolved()
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	ymd = start
+	for ; n > 0; n-- {
+		ymd.yyyymmdd = addDaysYMD(ymd.yyyymmdd, step)
+		var ok bool
+		if ymd, ok = ymd.scanBusinessDay(holidays, step); !ok {
+			return start
+		}
+	}
+	return ymd
+}
+
+// BusinessDayOnOrBefore returns the YMDFlag if its date is a business day, otherwise the most recent prior business day.
+// A nil `cal` has no holidays.  If there is no business day within maxNonBusinessDays, the date is returned unchanged.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) BusinessDayOnOrBefore(cal HolidayCalendar) YMDFlag {
+	ymd = ymd.resolved()
+	if day, ok := ymd.scanBusinessDay(cal, -1); ok {
+		return day
+	}
+	return ymd
+}
+
+// BusinessDayOnOrAfter returns the YMDFlag if its date is a business day, otherwise the next business day.
+// A nil `cal` has no holidays.  If there is no business day within maxNonBusinessDays, the date is returned unchanged.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) BusinessDayOnOrAfter(cal HolidayCalendar) YMDFlag {
+	ymd = ymd.resolved()
+	if day, ok := ymd.scanBusinessDay(cal, 1); ok {
+		return day
+	}
+	return ymd
+}
+
+// maxNonBusinessDays bounds the scan for the next business day, so that a HolidayCalendar reporting
+// every day as a holiday cannot loop forever.  No real calendar goes ten years without a business day.
+const maxNonBusinessDays = 3660
+
+// scanBusinessDay returns the first business day from the YMDFlag's date in the direction `step`,
+// including the date itself, or false if there is none within maxNonBusinessDays.
+func (ymd YMDFlag) scanBusinessDay(cal HolidayCalendar, step int) (YMDFlag, bool) {
+	for i := 0; i < maxNonBusinessDays; i++ {
+		if ymd.IsBusinessDay(cal) {
+			return ymd, true
+		}
+		ymd.yyyymmdd = addDaysYMD(ymd.yyyymmdd, step)
+	}
+	return ymd, false
+}
+
+// IsFirstBusinessDayOfWeek returns true if the YMDFlag's date is a business day and no earlier day
+// of its Monday-to-Sunday week is a business day, such as a Tuesday following a Monday holiday.
+// A nil `cal` has no holidays.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsFirstBusinessDayOfWeek(cal HolidayCalendar) bool {
+	ymd = ymd.resolved()
+	if !ymd.IsBusinessDay(cal) {
+		return false
+	}
+	day := ymd
+	for day.yyyymmdd = weekStartYMD(ymd.yyyymmdd, time.Monday); day.yyyymmdd < ymd.yyyymmdd; day.yyyymmdd = addDaysYMD(day.yyyymmdd, 1) {
+		if day.IsBusinessDay(cal) {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstBusinessDaysBetween returns the first business day of each month intersecting the inclusive range
+// from `start` to `end`, in ascending order.  Only business days falling within the range are returned,
+// so a month whose first business day precedes `start` is omitted.
+// A nil `cal` has no holidays.  The returned YMDFlags share the location of `start`.
+func FirstBusinessDaysBetween(start, end YMDFlag, cal HolidayCalendar) []YMDFlag {
+	start, end = start.resolved(), end.resolved()
+	var result []YMDFlag
+	year, month, _ := start.AsYearMonthDay()
+	for monthStart := 10000*year + 100*month + 1; monthStart <= end.yyyymmdd; {
+		day := start
+		for day.yyyymmdd = monthStart; day.yyyymmdd/100 == monthStart/100; day.yyyymmdd = addDaysYMD(day.yyyymmdd, 1) {
+			if day.IsBusinessDay(cal) {
+				if day.yyyymmdd >= start.yyyymmdd && day.yyyymmdd <= end.yyyymmdd {
+					result = append(result, day)
+				}
+				break
+			}
+		}
+		if month++; month > 12 {
+			year, month = year+1, 1
+		}
+		monthStart = 10000*year + 100*month + 1
+	}
+	return result
+}
+
+// BusinessDaysInRange returns the business days in the inclusive range from `start` to `end`, in ascending order,
+// as determined by IsBusinessDay.  A nil `cal`, including a nil HolidaySet, skips only weekends.
+// Returns nil if `end` is before `start`.  The returned YMDFlags share the location of `start`.
+// Nil dates are resolved to today.
+func BusinessDaysInRange(start, end YMDFlag, cal HolidayCalendar) []YMDFlag {
+	day, end := start.resolved(), end.resolved()
+	var result []YMDFlag
+	for ; day.yyyymmdd <= end.yyyymmdd; day.yyyymmdd = addDaysYMD(day.yyyymmdd, 1) {
+		if day.IsBusinessDay(cal) {
+			result = append(result, day)
+		}
+	}
+	return result
+}
+
+// BusinessDayCount returns the number of business days in the inclusive range from `start` to `end`,
+// the same as `len(BusinessDaysInRange(start, end, holidays))` but without materializing them.
+// The weekdays are counted arithmetically, as five per whole week plus the remainder,
+// so the cost depends on the number of holidays rather than on the length of the range.
+// Holidays falling on weekends are not subtracted twice.  A nil `holidays` skips only weekends.
+// Returns 0 if `end` is before `start`.  Nil dates are resolved to today.
+func BusinessDayCount(start, end YMDFlag, holidays HolidaySet) int {
+	first, last := start.resolved().yyyymmdd, end.resolved().yyyymmdd
+	if last < first {
+		return 0
+	}
+	firstDay, lastDay := daysFromCivil(first), daysFromCivil(last)
+	days := lastDay - firstDay + 1
+	count := 5 * (days / 7)
+	for day := firstDay + 7*(days/7); day <= lastDay; day++ {
+		if !isWeekendEpochDay(day) {
+			count++
+		}
+	}
+	for yyyymmdd := range holidays {
+		if yyyymmdd >= first && yyyymmdd <= last && !isWeekendEpochDay(daysFromCivil(yyyymmdd)) {
+			count--
+		}
+	}
+	return count
+}
+
+// isWeekendEpochDay returns true if the day `day` days after 1970-01-01, which was a Thursday,
+// is a Saturday or Sunday.
+func isWeekendEpochDay(day int) bool {
+	weekday := time.Weekday(((day % 7) + 7 + int(time.Thursday)) % 7)
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// TrailingBusinessDays returns the `n` business days ending at the YMDFlag's date, in ascending order.
+// If the YMDFlag's date is not a business day, the window ends at the prior business day.
+// A nil `cal` has no holidays.  Returns nil if `n` is not positive, or if any gap between business days
+// exceeds maxNonBusinessDays, as with a calendar reporting every day as a holiday.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) TrailingBusinessDays(n int, cal HolidayCalendar) []YMDFlag {
+	if n <= 0 {
+		return nil
+	}
+	day := ymd.resolved()
+	result := make([]YMDFlag, n)
+	for i := n - 1; i >= 0; i-- {
+		var ok bool
+		if day, ok = day.scanBusinessDay(cal, -1); !ok {
+			return nil
+		}
+		result[i] = day
+		day.yyyymmdd = addDaysYMD(day.yyyymmdd, -1)
+	}
+	return result
+}
+
+// BusinessDayProgress returns how far through the range's business days `asOf` is, in the range [0,1].
+// It is the position of `asOf` among the business days of the range, so it is 0 on the first business day
+// (or before it) and 1 on the last business day (or after it).  Non-business days count as the preceding business day.
+// A nil `cal` has no holidays.  Nil dates are resolved to today.
+func (r YMDRange) BusinessDayProgress(asOf YMDFlag, cal HolidayCalendar) float64 {
+	r = r.Ordered()
+	asOf = asOf.resolved()
+	total, elapsed := 0, 0
+	r.each(func(ymd YMDFlag) bool {
+		if ymd.IsBusinessDay(cal) {
+			total++
+			if ymd.yyyymmdd <= asOf.yyyymmdd {
+				elapsed++
+			}
+		}
+		return true
+	})
+	if total <= 1 {
+		if asOf.yyyymmdd >= r.End.resolved().yyyymmdd {
+			return 1
+		}
+		return 0
+	}
+	if elapsed == 0 {
+		return 0
+	}
+	return float64(elapsed-1) / float64(total-1)
+}
@@ -0,0 +1,241 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testHolidays returns a HolidayCalendar of the given `yyyymmdd` dates.
+func testHolidays(dates ...int) HolidayCalendar {
+	return HolidayFunc(func(ymd YMDFlag) bool {
+		for _, date := range dates {
+			if ymd.GetYMD() == date {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func ymdInts(flags []YMDFlag) []int {
+	ints := make([]int, len(flags))
+	for i, flag := range flags {
+		ints[i] = flag.GetYMD()
+	}
+	return ints
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	assert.True(t, mustYMD(t, 20230704).IsBusinessDay(nil), "Tuesday")
+	assert.False(t, mustYMD(t, 20230701).IsBusinessDay(nil), "Saturday")
+	assert.False(t, mustYMD(t, 20230702).IsBusinessDay(nil), "Sunday")
+	assert.False(t, mustYMD(t, 20230704).IsBusinessDay(testHolidays(20230704)), "holiday")
+}
+
+func TestFirstBusinessDaysBetween(t *testing.T) {
+	// Jul 1 2023 is a Saturday, Aug 1 is a Tuesday, Sep 1 is a Friday
+	result := FirstBusinessDaysBetween(mustYMD(t, 20230701), mustYMD(t, 20230930), nil)
+	assert.Equal(t, []int{20230703, 20230801, 20230901}, ymdInts(result))
+
+	// Sep 1 as a holiday pushes to Monday Sep 4
+	result = FirstBusinessDaysBetween(mustYMD(t, 20230701), mustYMD(t, 20230930), testHolidays(20230901))
+	assert.Equal(t, []int{20230703, 20230801, 20230904}, ymdInts(result))
+
+	// months whose first business day falls outside the range are omitted
+	result = FirstBusinessDaysBetween(mustYMD(t, 20230715), mustYMD(t, 20230831), nil)
+	assert.Equal(t, []int{20230801}, ymdInts(result))
+
+	assert.Empty(t, FirstBusinessDaysBetween(mustYMD(t, 20230930), mustYMD(t, 20230701), nil), "reversed range")
+}
+
+func TestBusinessDaysInRange(t *testing.T) {
+	// Thursday Jul 6 2023 through Tuesday Jul 11, crossing the weekend of Jul 8-9
+	loc := mustLoadLocation(t, "America/New_York")
+	result := BusinessDaysInRange(mustYMDIn(t, 20230706, loc), mustYMD(t, 20230711), nil)
+	assert.Equal(t, []int{20230706, 20230707, 20230710, 20230711}, ymdInts(result))
+	assert.Equal(t, loc, result[0].Location(), "location of start")
+
+	// Jul 4 2023 is a Tuesday holiday
+	var noHolidays HolidaySet
+	holidays := NewHolidaySet(mustYMD(t, 20230704))
+	result = BusinessDaysInRange(mustYMD(t, 20230701), mustYMD(t, 20230707), holidays)
+	assert.Equal(t, []int{20230703, 20230705, 20230706, 20230707}, ymdInts(result))
+	result = BusinessDaysInRange(mustYMD(t, 20230701), mustYMD(t, 20230707), noHolidays)
+	assert.Equal(t, []int{20230703, 20230704, 20230705, 20230706, 20230707}, ymdInts(result), "nil HolidaySet skips only weekends")
+
+	assert.Equal(t, []int{20230705}, ymdInts(BusinessDaysInRange(mustYMD(t, 20230705), mustYMD(t, 20230705), nil)), "single day")
+	assert.Empty(t, BusinessDaysInRange(mustYMD(t, 20230708), mustYMD(t, 20230709), nil), "weekend only")
+	assert.Empty(t, BusinessDaysInRange(mustYMD(t, 20230711), mustYMD(t, 20230706), nil), "reversed range")
+}
+
+func TestBusinessDayCount(t *testing.T) {
+	// Jul 4 2023 is a Tuesday holiday, Jul 8 a Saturday "holiday", and Dec 25 2022 a Sunday
+	holidays := NewHolidaySet(mustYMD(t, 20230704), mustYMD(t, 20230708), mustYMD(t, 20221225), mustYMD(t, 20240101))
+	assert.Equal(t, 4, BusinessDayCount(mustYMD(t, 20230701), mustYMD(t, 20230707), holidays))
+	assert.Equal(t, 5, BusinessDayCount(mustYMD(t, 20230701), mustYMD(t, 20230707), nil))
+	assert.Equal(t, 0, BusinessDayCount(mustYMD(t, 20230708), mustYMD(t, 20230709), holidays), "weekend only")
+	assert.Equal(t, 0, BusinessDayCount(mustYMD(t, 20230711), mustYMD(t, 20230706), nil), "reversed range")
+
+	// matches a naive iteration, for every start weekday and length up to a few weeks, and across years
+	for _, first := range []int{20221220, 20230626, 20230701, 20231228} {
+		for length := 0; length < 30; length++ {
+			start := mustYMD(t, first)
+			end := start.AddDays(length)
+			for _, set := range []HolidaySet{nil, holidays} {
+				naive := len(BusinessDaysInRange(start, end, set))
+				assert.Equal(t, naive, BusinessDayCount(start, end, set), "%d + %d days", first, length)
+			}
+		}
+	}
+	start, end := mustYMD(t, 19690101), mustYMD(t, 20301231)
+	assert.Equal(t, len(BusinessDaysInRange(start, end, holidays)), BusinessDayCount(start, end, holidays), "spanning the epoch")
+
+	setNow(t, time.Date(2023, time.July, 7, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 4, BusinessDayCount(mustYMDIn(t, 20230703, time.UTC), mustYMDIn(t, 0, time.UTC), holidays), "nil end is today")
+}
+
+// BenchmarkBusinessDayCount counts over a 10-year span, which does not iterate the days when holidays is nil.
+func BenchmarkBusinessDayCount(b *testing.B) {
+	start, end := YMDFlag{yyyymmdd: 20140101}, YMDFlag{yyyymmdd: 20231231}
+	for i := 0; i < b.N; i++ {
+		BusinessDayCount(start, end, nil)
+	}
+}
+
+func BenchmarkBusinessDaysInRange(b *testing.B) {
+	start, end := YMDFlag{yyyymmdd: 20140101}, YMDFlag{yyyymmdd: 20231231}
+	for i := 0; i < b.N; i++ {
+		_ = len(BusinessDaysInRange(start, end, nil))
+	}
+}
+
+func TestTrailingBusinessDays(t *testing.T) {
+	// Tuesday Jul 11 2023, crossing the weekend of Jul 8-9
+	result := mustYMD(t, 20230711).TrailingBusinessDays(5, nil)
+	assert.Equal(t, []int{20230705, 20230706, 20230707, 20230710, 20230711}, ymdInts(result))
+
+	// with the Jul 4 holiday
+	result = mustYMD(t, 20230707).TrailingBusinessDays(5, testHolidays(20230704))
+	assert.Equal(t, []int{20230630, 20230703, 20230705, 20230706, 20230707}, ymdInts(result))
+
+	// Sunday ends at the prior Friday
+	result = mustYMD(t, 20230709).TrailingBusinessDays(2, nil)
+	assert.Equal(t, []int{20230706, 20230707}, ymdInts(result))
+
+	assert.Nil(t, mustYMD(t, 20230709).TrailingBusinessDays(0, nil))
+}
+
+func TestIsFirstBusinessDayOfWeek(t *testing.T) {
+	// week of Mon Sep 4 2023, which is Labor Day in the US
+	laborDay := testHolidays(20230904)
+	assert.True(t, mustYMD(t, 20230905).IsFirstBusinessDayOfWeek(laborDay), "Tuesday after a Monday holiday")
+	assert.False(t, mustYMD(t, 20230904).IsFirstBusinessDayOfWeek(laborDay), "the holiday itself")
+	assert.False(t, mustYMD(t, 20230906).IsFirstBusinessDayOfWeek(laborDay), "mid-week")
+
+	assert.True(t, mustYMD(t, 20230904).IsFirstBusinessDayOfWeek(nil), "Monday without holidays")
+	assert.False(t, mustYMD(t, 20230905).IsFirstBusinessDayOfWeek(nil), "Tuesday without holidays")
+	assert.False(t, mustYMD(t, 20230910).IsFirstBusinessDayOfWeek(nil), "Sunday")
+}
+
+func TestBusinessDayProgress(t *testing.T) {
+	// Mon Jul 3 2023 through Mon Jul 17 2023 has 11 business days
+	r := mustRange(t, 20230703, 20230717)
+	assert.Equal(t, 0.0, r.BusinessDayProgress(mustYMD(t, 20230703), nil), "at Start")
+	assert.Equal(t, 1.0, r.BusinessDayProgress(mustYMD(t, 20230717), nil), "at End")
+	assert.Equal(t, 0.5, r.BusinessDayProgress(mustYMD(t, 20230710), nil), "midway")
+	assert.Equal(t, 0.4, r.BusinessDayProgress(mustYMD(t, 20230708), nil), "weekend counts as the prior Friday")
+	assert.Equal(t, 0.0, r.BusinessDayProgress(mustYMD(t, 20230601), nil), "clamped before Start")
+	assert.Equal(t, 1.0, r.BusinessDayProgress(mustYMD(t, 20230801), nil), "clamped after End")
+
+	// the Jul 4 holiday leaves 10 business days
+	assert.InDelta(t, 4.0/9.0, r.BusinessDayProgress(mustYMD(t, 20230710), testHolidays(20230704)), 1e-12, "with holiday")
+
+	weekend := mustRange(t, 20230708, 20230709)
+	assert.Equal(t, 0.0, weekend.BusinessDayProgress(mustYMD(t, 20230708), nil), "no business days")
+	assert.Equal(t, 1.0, weekend.BusinessDayProgress(mustYMD(t, 20230709), nil), "no business days at End")
+}
+
+func TestBusinessDayOnOrBefore(t *testing.T) {
+	assert.Equal(t, 20230707, mustYMD(t, 20230709).BusinessDayOnOrBefore(nil).GetYMD(), "Sunday resolves to Friday")
+	assert.Equal(t, 20230707, mustYMD(t, 20230708).BusinessDayOnOrBefore(nil).GetYMD(), "Saturday resolves to Friday")
+	assert.Equal(t, 20230706, mustYMD(t, 20230706).BusinessDayOnOrBefore(nil).GetYMD(), "business day is itself")
+
+	// Mon Sep 4 2023 was Labor Day in the US
+	laborDay := testHolidays(20230904)
+	assert.Equal(t, 20230901, mustYMD(t, 20230904).BusinessDayOnOrBefore(laborDay).GetYMD(), "holiday Monday resolves to Friday")
+	assert.Equal(t, 20230901, mustYMD(t, 20230903).BusinessDayOnOrBefore(laborDay).GetYMD(), "Sunday before a holiday")
+}
+
+func TestBusinessDayOnOrAfter(t *testing.T) {
+	assert.Equal(t, 20230710, mustYMD(t, 20230708).BusinessDayOnOrAfter(nil).GetYMD(), "Saturday resolves to Monday")
+	assert.Equal(t, 20230710, mustYMD(t, 20230709).BusinessDayOnOrAfter(nil).GetYMD(), "Sunday resolves to Monday")
+	assert.Equal(t, 20230706, mustYMD(t, 20230706).BusinessDayOnOrAfter(nil).GetYMD(), "business day is itself")
+
+	// Fri Nov 24 2023 as a holiday
+	holiday := testHolidays(20231124)
+	assert.Equal(t, 20231127, mustYMD(t, 20231124).BusinessDayOnOrAfter(holiday).GetYMD(), "holiday Friday resolves to Monday")
+	assert.Equal(t, 20231123, mustYMD(t, 20231123).BusinessDayOnOrAfter(holiday).GetYMD(), "day before the holiday")
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	friday := mustYMDIn(t, 20230707, time.UTC)
+	assert.Equal(t, 20230710, friday.AddBusinessDays(1).GetYMD(), "Friday + 1 is Monday")
+	assert.Equal(t, 20230714, friday.AddBusinessDays(5).GetYMD(), "Friday + 5 is the next Friday")
+	assert.Equal(t, 20230706, friday.AddBusinessDays(-1).GetYMD(), "Friday - 1 is Thursday")
+	assert.Equal(t, time.UTC, friday.AddBusinessDays(1).Location(), "location is preserved")
+
+	monday := mustYMD(t, 20230710)
+	assert.Equal(t, 20230707, monday.AddBusinessDays(-1).GetYMD(), "Monday - 1 crosses the weekend")
+	assert.Equal(t, 20230703, monday.AddBusinessDays(-5).GetYMD(), "Monday - 5 is the prior Monday")
+
+	saturday := mustYMD(t, 20230708)
+	assert.Equal(t, 20230708, saturday.AddBusinessDays(0).GetYMD(), "zero stays put")
+	assert.Equal(t, 20230710, saturday.AddBusinessDays(1).GetYMD(), "Saturday + 1 is Monday")
+	assert.Equal(t, 20230707, saturday.AddBusinessDays(-1).GetYMD(), "Saturday - 1 is Friday")
+}
+
+func TestHolidaySet(t *testing.T) {
+	holidays := NewHolidaySet(mustYMD(t, 20230704), mustYMD(t, 20231225), YMDFlag{})
+	assert.Len(t, holidays, 2, "nil flags are ignored")
+	holidays.Add(mustYMDIn(t, 20230904, time.UTC))
+	holidays.Add(mustYMD(t, 20230904))
+	assert.Len(t, holidays, 3, "duplicates collapse")
+
+	assert.True(t, holidays.IsHoliday(mustYMD(t, 20230704)))
+	assert.True(t, holidays.IsHoliday(mustYMDIn(t, 20231225, time.UTC)), "location is ignored")
+	assert.False(t, holidays.IsHoliday(mustYMD(t, 20230705)))
+	assert.False(t, HolidaySet(nil).IsHoliday(mustYMD(t, 20230704)), "nil set")
+
+	// Tue Jul 4 2023 is a weekday holiday
+	assert.False(t, mustYMD(t, 20230704).IsBusinessDay(holidays))
+	assert.True(t, mustYMD(t, 20230705).IsBusinessDay(holidays))
+
+	monday := mustYMD(t, 20230703)
+	assert.Equal(t, 20230705, monday.AddBusinessDaysCal(1, holidays).GetYMD(), "holiday is skipped")
+	assert.Equal(t, 20230704, monday.AddBusinessDaysCal(1, nil).GetYMD(), "no holidays")
+	assert.Equal(t, 20230711, monday.AddBusinessDaysCal(5, holidays).GetYMD(), "holiday and weekend are skipped")
+	assert.Equal(t, 20230703, mustYMD(t, 20230705).AddBusinessDaysCal(-1, holidays).GetYMD(), "holiday is skipped backward")
+	assert.Equal(t, monday.AddBusinessDays(5), monday.AddBusinessDaysCal(5, nil), "AddBusinessDays has no holidays")
+}
+
+func TestAlwaysHolidayCalendar(t *testing.T) {
+	closed := HolidayFunc(func(YMDFlag) bool { return true })
+	ymdFlag := mustYMDIn(t, 20230704, time.UTC)
+	assert.Equal(t, ymdFlag, ymdFlag.AddBusinessDaysCal(1, closed), "falls back to the date")
+	assert.Equal(t, ymdFlag, ymdFlag.AddBusinessDaysCal(-3, closed), "falls back to the date")
+	assert.Equal(t, ymdFlag, ymdFlag.BusinessDayOnOrBefore(closed), "falls back to the date")
+	assert.Equal(t, ymdFlag, ymdFlag.BusinessDayOnOrAfter(closed), "falls back to the date")
+	assert.Nil(t, ymdFlag.TrailingBusinessDays(5, closed))
+
+	// a long closure within the limit is still crossed
+	reopens := mustYMD(t, 20300101)
+	closure := HolidayFunc(func(ymd YMDFlag) bool { return ymd.Before(reopens) && ymd.After(mustYMD(t, 20230704)) })
+	assert.Equal(t, 20300101, ymdFlag.AddBusinessDaysCal(1, closure).GetYMD())
+	assert.Equal(t, 20300101, mustYMD(t, 20230705).BusinessDayOnOrAfter(closure).GetYMD())
+	assert.Equal(t, 20230704, mustYMD(t, 20291231).BusinessDayOnOrBefore(closure).GetYMD())
+	assert.Equal(t, []int{20230703, 20230704, 20300101}, ymdInts(reopens.TrailingBusinessDays(3, closure)))
+}
@@ -0,0 +1,389 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekday returns the day of the week of the YMDFlag's civil date, independent of its location.
+// This differs from `AsTime().Weekday()` where midnight does not exist in the location and AsTime is on the previous day.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) Weekday() time.Weekday {
+	return YMDToTime(ymd.resolved().yyyymmdd, time.UTC).Weekday()
+}
+
+// DaysUntilWeekday returns the number of days, from 0 through 6, forward from the YMDFlag's date
+// to the next `target` weekday, which is 0 if the date already falls on `target`.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) DaysUntilWeekday(target time.Weekday) int {
+	return (int(target) - int(ymd.Weekday()) + 7) % 7
+}
+
+// NextWeekday returns the earliest date on or after the YMDFlag's date which falls on `target`,
+// which is DaysUntilWeekday days later, so it is the date itself if it already falls on `target`.
+// The location is the same.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) NextWeekday(target time.Weekday) YMDFlag {
+	return ymd.AddDays(ymd.DaysUntilWeekday(target))
+}
+
+// PrevWeekday returns the latest date on or before the YMDFlag's date which falls on `target`,
+// so it is the date itself if it already falls on `target`.  It is the same as WeekStart(target).
+// The location is the same.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) PrevWeekday(target time.Weekday) YMDFlag {
+	return ymd.WeekStart(target)
+}
+
+// IsWeekend returns true if the YMDFlag's date is a Saturday or Sunday.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsWeekend() bool {
+	weekday := ymd.Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// ISOWeek returns the ISO 8601 year and week number of the YMDFlag's date, as `time.Time.ISOWeek` does.
+// Early January dates may belong to the last week of the previous year,
+// and late December dates may belong to week 1 of the following year.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) ISOWeek() (year int, week int) {
+	return YMDToTime(ymd.resolved().yyyymmdd, time.UTC).ISOWeek()
+}
+
+// AsISOWeekString returns the ISO 8601 week of the YMDFlag's date as `"YYYY-Www"`, for example `"2023-W27"`.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsISOWeekString() string {
+	year, week := ymd.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// DayOfYear returns the ordinal day of the year of the YMDFlag's date, from 1 through 365, or 366 in leap years.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) DayOfYear() int {
+	return YMDToTime(ymd.resolved().yyyymmdd, time.UTC).YearDay()
+}
+
+// AsOrdinalString returns the ISO 8601 ordinal date of the YMDFlag as `"YYYY-DDD"`, for example `"2023-185"`.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsOrdinalString() string {
+	ymd = ymd.resolved()
+	year, _, _ := ymd.AsYearMonthDay()
+	return fmt.Sprintf("%04d-%03d", year, ymd.DayOfYear())
+}
+
+// ParseJulian returns a YMDFlag in location `loc` for the ordinal date `yyyyddd`, the year followed by
+// the day of the year, as used by legacy mainframe feeds; for example 2023185 is 2023-07-04.
+// Returns a non-nil error if the day is not from 1 through 365, or 366 in leap years.
+// A `yyyyddd` of 0 results in a nil YMDFlag.
+func ParseJulian(yyyyddd int, loc *time.Location) (YMDFlag, error) {
+	if yyyyddd == 0 {
+		return YMDFlag{loc: loc}, nil
+	}
+	if yyyyddd < 0 || yyyyddd > 9999366 {
+		return YMDFlag{}, fmt.Errorf("yyyyddd %d is not of the form YYYYDDD: %w", yyyyddd, ErrOutOfRange)
+	}
+	year, day := yyyyddd/1000, yyyyddd%1000
+	if year < MinYear {
+		return YMDFlag{}, fmt.Errorf("yyyyddd year 0000 is not supported, years are 0001 through 9999: %w", ErrOutOfRange)
+	}
+	daysInYear := 365
+	if IsLeapYear(year) {
+		daysInYear = 366
+	}
+	if day < 1 || day > daysInYear {
+		return YMDFlag{}, fmt.Errorf("day %d is invalid for %04d: %w", day, year, ErrOutOfRange)
+	}
+	return YMDFlag{yyyymmdd: addDaysYMD(10000*year+101, day-1), loc: loc}, nil
+}
+
+// AsJulian returns the YMDFlag as the ordinal date `YYYYDDD`, the year followed by the day of the year,
+// which is the reverse of ParseJulian.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsJulian() int {
+	ymd = ymd.resolved()
+	return 1000*(ymd.yyyymmdd/10000) + ymd.DayOfYear()
+}
+
+// NewYMDFlagFromEpochDay returns a YMDFlag in location `loc` for the date `n` days after 1970-01-01,
+// which is the reverse of AsEpochDay.  Returns a non-nil error wrapping ErrOutOfRange if the date
+// is not within years MinYear through MaxYear.
+func NewYMDFlagFromEpochDay(n int64, loc *time.Location) (YMDFlag, error) {
+	if n < int64(daysFromCivil(10000*MinYear+101)) || n > int64(daysFromCivil(10000*MaxYear+1231)) {
+		return YMDFlag{}, fmt.Errorf("epoch day %d is not within years %04d through %04d: %w", n, MinYear, MaxYear, ErrOutOfRange)
+	}
+	return YMDFlag{yyyymmdd: addDaysYMD(19700101, int(n)), loc: loc}, nil
+}
+
+// AsEpochDay returns the number of days from 1970-01-01 to the YMDFlag's date, negative for earlier dates.
+// Unlike `YYYYMMDD`, consecutive dates have consecutive epoch days, making them dense, sortable integer keys.
+// The count is of calendar days, so it is the same for a date in any location, and equals the Unix time
+// of the date's midnight in UTC divided by 86400.  A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsEpochDay() int64 {
+	return int64(daysFromCivil(ymd.resolved().yyyymmdd))
+}
+
+// Season returns the meteorological season of the YMDFlag's date: "Winter", "Spring", "Summer", or "Autumn".
+// Seasons are whole months: December-February is Winter in the Northern hemisphere.
+// If `hemisphere` is "southern" (case-insensitive) the seasons are flipped; any other value means Northern.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) Season(hemisphere string) string {
+	_, month, _ := ymd.resolved().AsYearMonthDay()
+	seasons := [4]string{"Winter", "Spring", "Summer", "Autumn"}
+	index := (month % 12) / 3 // Dec,Jan,Feb => 0
+	if strings.EqualFold(hemisphere, "southern") {
+		index = (index + 2) % 4
+	}
+	return seasons[index]
+}
+
+// YearFractionElapsed returns how far through its year the YMDFlag's date is, in the range [0,1).
+// It is computed as (dayOfYear-1)/daysInYear, so January 1 is 0.0 and leap years use 366 days.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) YearFractionElapsed() float64 {
+	ymd = ymd.resolved()
+	year, _, _ := ymd.AsYearMonthDay()
+	daysInYear := 365
+	if IsLeapYear(year) {
+		daysInYear = 366
+	}
+	return float64(ymd.DayOfYear()-1) / float64(daysInYear)
+}
+
+// YearHasLeapDay returns true if the YMDFlag's year contains a February 29.
+// It describes the whole year, regardless of whether the YMDFlag's date falls before or after February 29.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) YearHasLeapDay() bool {
+	year, _, _ := ymd.resolved().AsYearMonthDay()
+	return IsLeapYear(year)
+}
+
+// DaysInMonth returns the number of days, 28 through 31, in the month of the YMDFlag's date.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) DaysInMonth() int {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	return daysInMonth(year, month)
+}
+
+// IsLeapYear returns true if the Gregorian `year` has a February 29:
+// years divisible by 4, except centuries not divisible by 400.
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// Quarter returns the calendar quarter, 1 through 4, of the YMDFlag's date.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) Quarter() int {
+	_, month, _ := ymd.resolved().AsYearMonthDay()
+	return (month-1)/3 + 1
+}
+
+// FiscalQuarter returns the quarter, 1 through 4, of the YMDFlag's date within a fiscal year
+// beginning on the first day of `fyStartMonth`.  For example, with an April start, January is in Q4.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) FiscalQuarter(fyStartMonth time.Month) int {
+	_, month, _ := ymd.resolved().AsYearMonthDay()
+	return (month-int(fyStartMonth)+12)%12/3 + 1
+}
+
+// FiscalYear returns the fiscal year of the YMDFlag's date, for a fiscal year beginning on the first day
+// of `fyStartMonth`.  Fiscal years are numbered by the calendar year in which they begin, so with an
+// April start, January 2024 is in fiscal year 2023.  With a January start, it is the calendar year.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) FiscalYear(fyStartMonth time.Month) int {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	if month < int(fyStartMonth) {
+		return year - 1
+	}
+	return year
+}
+
+// MonthStart returns the first day of the month containing the YMDFlag's date, with the same location.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) MonthStart() YMDFlag {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	ymd.yyyymmdd = 10000*year + 100*month + 1
+	return ymd
+}
+
+// MonthEnd returns the last day of the month containing the YMDFlag's date, with the same location.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) MonthEnd() YMDFlag {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	ymd.yyyymmdd = monthEndYMD(year, month)
+	return ymd
+}
+
+// WeekStart returns the latest date on or before the YMDFlag's date which falls on `firstDay`,
+// which is the first day of its week when weeks begin on `firstDay`, such as time.Monday for ISO 8601
+// or time.Sunday in the US.  The location is the same.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) WeekStart(firstDay time.Weekday) YMDFlag {
+	ymd.yyyymmdd = weekStartYMD(ymd.resolved().yyyymmdd, firstDay)
+	return ymd
+}
+
+// WeekEnd returns the last day of the week containing the YMDFlag's date, when weeks begin on `firstDay`,
+// which is 6 days after WeekStart.  The location is the same.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) WeekEnd(firstDay time.Weekday) YMDFlag {
+	ymd.yyyymmdd = addDaysYMD(weekStartYMD(ymd.resolved().yyyymmdd, firstDay), 6)
+	return ymd
+}
+
+// Unit is a calendar unit which a YMDFlag may be truncated to.
+type Unit int
+
+const (
+	UnitDay Unit = iota
+	UnitWeek
+	UnitMonth
+	UnitQuarter
+	UnitYear
+)
+
+// Truncate returns the first day of the `unit` containing the YMDFlag's date, with the same location.
+// Weeks begin on Monday, as in ISO 8601; use WeekStart for weeks beginning on another day.
+// UnitDay returns the date itself.  Truncate panics for an unknown unit.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) Truncate(unit Unit) YMDFlag {
+	switch unit {
+	case UnitDay:
+		return ymd.resolved()
+	case UnitWeek:
+		return ymd.WeekStart(time.Monday)
+	case UnitMonth:
+		return ymd.MonthStart()
+	case UnitQuarter:
+		return ymd.QuarterStart()
+	case UnitYear:
+		ymd.yyyymmdd = (ymd.resolved().yyyymmdd/10000)*10000 + 101
+		return ymd
+	default:
+		panic(fmt.Sprintf("ymdflag: unknown Truncate unit %d", unit))
+	}
+}
+
+// QuarterStart returns the first day of the calendar quarter containing the YMDFlag's date.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) QuarterStart() YMDFlag {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	startMonth := ((month-1)/3)*3 + 1
+	ymd.yyyymmdd = 10000*year + 100*startMonth + 1
+	return ymd
+}
+
+// QuarterEnd returns the last day of the calendar quarter containing the YMDFlag's date.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) QuarterEnd() YMDFlag {
+	year, month, _ := ymd.resolved().AsYearMonthDay()
+	endMonth := ((month-1)/3)*3 + 3
+	ymd.yyyymmdd = monthEndYMD(year, endMonth)
+	return ymd
+}
+
+// IsQuarterStart returns true if the YMDFlag's date is the first day of its calendar quarter.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsQuarterStart() bool {
+	ymd = ymd.resolved()
+	return ymd.yyyymmdd == ymd.QuarterStart().yyyymmdd
+}
+
+// IsQuarterEnd returns true if the YMDFlag's date is the last day of its calendar quarter.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsQuarterEnd() bool {
+	ymd = ymd.resolved()
+	return ymd.yyyymmdd == ymd.QuarterEnd().yyyymmdd
+}
+
+// QuarterProgress returns the whole days of the YMDFlag's quarter which have elapsed before its date,
+// and the days remaining from its date through QuarterEnd inclusive.  The two sum to the length of the quarter.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) QuarterProgress() (elapsed, remaining int) {
+	ymd = ymd.resolved()
+	start := daysSinceEpoch(ymd.QuarterStart().yyyymmdd)
+	end := daysSinceEpoch(ymd.QuarterEnd().yyyymmdd)
+	day := daysSinceEpoch(ymd.yyyymmdd)
+	return day - start, end - day + 1
+}
+
+// AgeInDays returns the number of whole days from the YMDFlag's date until today in its location.
+// Dates in the future have a negative age.  A nil YMDFlag is today, so has an age of 0.
+// Today is determined using NowFunc.
+func (ymd YMDFlag) AgeInDays() int {
+	return daysSinceEpoch(ymd.today()) - daysSinceEpoch(ymd.resolved().yyyymmdd)
+}
+
+// IsToday returns true if the YMDFlag's date is today in its location, or local time if that is nil.
+// A nil YMDFlag is today by definition, unless it has a default date, and is not mutated.
+// Today is determined using NowFunc.
+func (ymd YMDFlag) IsToday() bool {
+	return ymd.resolved().yyyymmdd == ymd.today()
+}
+
+// IsPast returns true if the YMDFlag's date is before today in its location, or local time if that is nil.
+// A nil YMDFlag is today, so is never in the past, unless it has a default date.  Today is determined using NowFunc.
+func (ymd YMDFlag) IsPast() bool {
+	return ymd.resolved().yyyymmdd < ymd.today()
+}
+
+// IsFuture returns true if the YMDFlag's date is after today in its location, or local time if that is nil.
+// A nil YMDFlag is today, so is never in the future, unless it has a default date.  Today is determined using NowFunc.
+func (ymd YMDFlag) IsFuture() bool {
+	return ymd.resolved().yyyymmdd > ymd.today()
+}
+
+// MonthEndsBetween returns the last day of each month within the inclusive range from `start` to `end`,
+// in ascending order.  Month ends outside of the range are omitted.
+// The returned YMDFlags share the location of `start`.  Nil dates are resolved to today.
+func MonthEndsBetween(start, end YMDFlag) []YMDFlag {
+	start, end = start.resolved(), end.resolved()
+	var result []YMDFlag
+	year, month, _ := start.AsYearMonthDay()
+	for monthEnd := monthEndYMD(year, month); monthEnd <= end.yyyymmdd; monthEnd = monthEndYMD(year, month) {
+		if monthEnd >= start.yyyymmdd {
+			ymd := start
+			ymd.yyyymmdd = monthEnd
+			result = append(result, ymd)
+		}
+		if month++; month > 12 {
+			year, month = year+1, 1
+		}
+	}
+	return result
+}
+
+// GroupByWeek groups the `flags` by the week containing each date, where weeks begin on `weekStartsOn`.
+// The map is keyed by the integral `yyyymmdd` of each week's first day.
+// Input order is preserved within each group.  Nil YMDFlags are resolved to today.
+func GroupByWeek(flags []YMDFlag, weekStartsOn time.Weekday) map[int][]YMDFlag {
+	groups := make(map[int][]YMDFlag)
+	for _, ymd := range flags {
+		ymd = ymd.resolved()
+		key := weekStartYMD(ymd.yyyymmdd, weekStartsOn)
+		groups[key] = append(groups[key], ymd)
+	}
+	return groups
+}
+
+// weekStartYMD returns the `yyyymmdd` of the latest date on or before the given `yyyymmdd` falling on `firstDay`.
+func weekStartYMD(yyyymmdd int, firstDay time.Weekday) int {
+	weekday := YMDToTime(yyyymmdd, time.UTC).Weekday()
+	return addDaysYMD(yyyymmdd, -((int(weekday) - int(firstDay) + 7) % 7))
+}
+
+// daysSinceEpoch returns the number of days from 1970-01-01 to the `yyyymmdd`.
+// It is computed in UTC, so DST transitions do not affect the count.
+func daysSinceEpoch(yyyymmdd int) int {
+	return int(YMDToTime(yyyymmdd, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+// monthEndYMD returns the `yyyymmdd` of the last day of the given month.
+func monthEndYMD(year, month int) int {
+	// day 0 of the following month is the last day of this month
+	return civilToYMD(time.Date(year, time.Month(month+1), 0, 0, 0, 0, 0, time.UTC))
+}
+
+// addDaysYMD returns the `yyyymmdd` which is `days` calendar days after the given `yyyymmdd`.
+func addDaysYMD(yyyymmdd int, days int) int {
+	return civilToYMD(YMDToTime(yyyymmdd, time.UTC).AddDate(0, 0, days))
+}
@@ -0,0 +1,454 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeason(t *testing.T) {
+	cases := []struct {
+		yyyymmdd int
+		northern string
+		southern string
+	}{
+		{20230115, "Winter", "Summer"},
+		{20231201, "Winter", "Summer"},
+		{20230228, "Winter", "Summer"},
+		{20230301, "Spring", "Autumn"},
+		{20230704, "Summer", "Winter"},
+		{20231031, "Autumn", "Spring"},
+	}
+	for _, c := range cases {
+		ymd := mustYMD(t, c.yyyymmdd)
+		assert.Equal(t, c.northern, ymd.Season("northern"), "northern %d", c.yyyymmdd)
+		assert.Equal(t, c.northern, ymd.Season(""), "default is northern %d", c.yyyymmdd)
+		assert.Equal(t, c.southern, ymd.Season("southern"), "southern %d", c.yyyymmdd)
+		assert.Equal(t, c.southern, ymd.Season("Southern"), "case-insensitive %d", c.yyyymmdd)
+	}
+}
+
+func TestYearFractionElapsed(t *testing.T) {
+	assert.Equal(t, 0.0, mustYMD(t, 20230101).YearFractionElapsed(), "Jan 1 is the start of the year")
+	assert.InDelta(t, 364.0/365.0, mustYMD(t, 20231231).YearFractionElapsed(), 1e-12, "Dec 31 of a common year")
+	assert.InDelta(t, 365.0/366.0, mustYMD(t, 20241231).YearFractionElapsed(), 1e-12, "Dec 31 of a leap year")
+	assert.Equal(t, 0.5, mustYMD(t, 20240702).YearFractionElapsed(), "Jul 2 is day 184 of 366")
+	assert.Less(t, mustYMD(t, 20241231).YearFractionElapsed(), 1.0)
+}
+
+func TestMonthStartEnd(t *testing.T) {
+	assert.Equal(t, 20240201, mustYMD(t, 20240215).MonthStart().GetYMD())
+	assert.Equal(t, 20240229, mustYMD(t, 20240215).MonthEnd().GetYMD(), "leap year February")
+	assert.Equal(t, 20230228, mustYMD(t, 20230201).MonthEnd().GetYMD(), "non-leap year February")
+	assert.Equal(t, 21000228, mustYMD(t, 21000201).MonthEnd().GetYMD(), "century non-leap year")
+	assert.Equal(t, 20230731, mustYMD(t, 20230731).MonthEnd().GetYMD(), "31-day month")
+	assert.Equal(t, 20230430, mustYMD(t, 20230401).MonthEnd().GetYMD(), "30-day month")
+	assert.Equal(t, 20231201, mustYMD(t, 20231231).MonthStart().GetYMD())
+
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	assert.Equal(t, tokyo, mustYMDIn(t, 20230704, tokyo).MonthStart().Location(), "location is preserved")
+	assert.Equal(t, tokyo, mustYMDIn(t, 20230704, tokyo).MonthEnd().Location(), "location is preserved")
+}
+
+func TestWeekStartEnd(t *testing.T) {
+	wednesday := mustYMD(t, 20230705)
+	assert.Equal(t, 20230703, wednesday.WeekStart(time.Monday).GetYMD(), "Monday start")
+	assert.Equal(t, 20230709, wednesday.WeekEnd(time.Monday).GetYMD(), "Monday start ends Sunday")
+	assert.Equal(t, 20230702, wednesday.WeekStart(time.Sunday).GetYMD(), "Sunday start")
+	assert.Equal(t, 20230708, wednesday.WeekEnd(time.Sunday).GetYMD(), "Sunday start ends Saturday")
+
+	sunday := mustYMD(t, 20230709)
+	assert.Equal(t, 20230703, sunday.WeekStart(time.Monday).GetYMD())
+	assert.Equal(t, 20230709, sunday.WeekEnd(time.Monday).GetYMD(), "on the last day")
+	assert.Equal(t, 20230709, sunday.WeekStart(time.Sunday).GetYMD(), "on the first day")
+	assert.Equal(t, 20230715, sunday.WeekEnd(time.Sunday).GetYMD())
+
+	assert.Equal(t, 20221226, mustYMD(t, 20230101).WeekStart(time.Monday).GetYMD(), "across years")
+	assert.Equal(t, 20240303, mustYMD(t, 20240227).WeekEnd(time.Monday).GetYMD(), "across a leap day")
+
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	assert.Equal(t, tokyo, mustYMDIn(t, 20230705, tokyo).WeekStart(time.Monday).Location(), "location is preserved")
+	assert.Equal(t, tokyo, mustYMDIn(t, 20230705, tokyo).WeekEnd(time.Monday).Location(), "location is preserved")
+}
+
+func TestQuarterStartEnd(t *testing.T) {
+	assert.Equal(t, 20230101, mustYMD(t, 20230215).QuarterStart().GetYMD())
+	assert.Equal(t, 20230331, mustYMD(t, 20230215).QuarterEnd().GetYMD())
+	assert.Equal(t, 20230401, mustYMD(t, 20230630).QuarterStart().GetYMD())
+	assert.Equal(t, 20230630, mustYMD(t, 20230630).QuarterEnd().GetYMD())
+	assert.Equal(t, 20231001, mustYMD(t, 20231231).QuarterStart().GetYMD())
+	assert.Equal(t, 20231231, mustYMD(t, 20231231).QuarterEnd().GetYMD())
+}
+
+func TestTruncate(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Paris")
+	ymdFlag := mustYMDIn(t, 20230817, loc) // a Thursday
+	cases := map[Unit]int{
+		UnitDay:     20230817,
+		UnitWeek:    20230814,
+		UnitMonth:   20230801,
+		UnitQuarter: 20230701,
+		UnitYear:    20230101,
+	}
+	for unit, expected := range cases {
+		truncated := ymdFlag.Truncate(unit)
+		assert.Equal(t, expected, truncated.GetYMD(), "unit %d", unit)
+		assert.Equal(t, loc, truncated.Location(), "unit %d", unit)
+		assert.Equal(t, truncated, truncated.Truncate(unit), "idempotent for unit %d", unit)
+	}
+	assert.Equal(t, 20230817, ymdFlag.GetYMD(), "receiver is unchanged")
+	assert.Equal(t, 20230814, mustYMD(t, 20230814).Truncate(UnitWeek).GetYMD(), "Monday is its own week start")
+	assert.Equal(t, 20221226, mustYMD(t, 20230101).Truncate(UnitWeek).GetYMD(), "week spans the year boundary")
+	assert.Panics(t, func() { ymdFlag.Truncate(Unit(99)) })
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, 20230704, zero.Truncate(UnitDay).GetYMD(), "nil resolves to today")
+	assert.Equal(t, 20230101, zero.Truncate(UnitYear).GetYMD(), "nil resolves to today")
+	assert.True(t, zero.IsZero(), "nil receiver is unchanged")
+}
+
+func TestQuarterProgress(t *testing.T) {
+	// Q3 2023 runs Jul 1 through Sep 30, which is 92 days
+	elapsed, remaining := mustYMD(t, 20230701).QuarterProgress()
+	assert.Equal(t, 0, elapsed, "first day of quarter")
+	assert.Equal(t, 92, remaining, "first day of quarter")
+
+	elapsed, remaining = mustYMD(t, 20230930).QuarterProgress()
+	assert.Equal(t, 91, elapsed, "last day of quarter")
+	assert.Equal(t, 1, remaining, "last day of quarter")
+
+	elapsed, remaining = mustYMD(t, 20230815).QuarterProgress()
+	assert.Equal(t, 45, elapsed, "mid quarter")
+	assert.Equal(t, 92, elapsed+remaining, "mid quarter sums to the quarter length")
+
+	// Q1 2024 is a leap quarter of 91 days
+	elapsed, remaining = mustYMD(t, 20240301).QuarterProgress()
+	assert.Equal(t, 91, elapsed+remaining, "leap quarter length")
+}
+
+func TestDayOfYear(t *testing.T) {
+	assert.Equal(t, 1, mustYMD(t, 20230101).DayOfYear())
+	assert.Equal(t, 185, mustYMD(t, 20230704).DayOfYear())
+	assert.Equal(t, 365, mustYMD(t, 20231231).DayOfYear(), "non-leap year")
+	assert.Equal(t, 366, mustYMD(t, 20241231).DayOfYear(), "leap year")
+	assert.Equal(t, 60, mustYMD(t, 20240229).DayOfYear())
+
+	assert.Equal(t, "2023-185", mustYMD(t, 20230704).AsOrdinalString())
+	assert.Equal(t, "2023-001", mustYMD(t, 20230101).AsOrdinalString())
+	assert.Equal(t, "2023-365", mustYMD(t, 20231231).AsOrdinalString())
+	assert.Equal(t, "2024-366", mustYMD(t, 20241231).AsOrdinalString())
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 185, mustYMDIn(t, 0, time.UTC).DayOfYear(), "nil is today")
+	assert.Equal(t, "2023-185", mustYMDIn(t, 0, time.UTC).AsOrdinalString(), "nil is today")
+}
+
+func TestParseJulian(t *testing.T) {
+	loc := mustLoadLocation(t, "America/Chicago")
+	cases := map[int]int{
+		2023001: 20230101,
+		2023185: 20230704,
+		2023365: 20231231,
+		2024060: 20240229,
+		2024366: 20241231,
+		1001:    10101,
+		1032:    10201,
+	}
+	for yyyyddd, yyyymmdd := range cases {
+		ymdFlag, err := ParseJulian(yyyyddd, loc)
+		assert.NoError(t, err, yyyyddd)
+		assert.Equal(t, yyyymmdd, ymdFlag.GetYMD(), yyyyddd)
+		assert.Equal(t, loc, ymdFlag.Location())
+		assert.Equal(t, yyyyddd, ymdFlag.AsJulian(), "round trip")
+	}
+
+	for _, yyyyddd := range []int{2023366, 2100366, 2024367, 2023000, -2023001, 99999001} {
+		_, err := ParseJulian(yyyyddd, loc)
+		assert.ErrorIs(t, err, ErrOutOfRange, yyyyddd)
+	}
+	_, err := ParseJulian(2000366, loc)
+	assert.NoError(t, err, "2000 is a leap year")
+
+	zero, err := ParseJulian(0, loc)
+	assert.NoError(t, err)
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, loc, zero.Location())
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 2023185, mustYMDIn(t, 0, time.UTC).AsJulian(), "nil is today")
+}
+
+func TestEpochDay(t *testing.T) {
+	assert.Equal(t, int64(0), mustYMD(t, 19700101).AsEpochDay(), "the Unix epoch")
+	assert.Equal(t, int64(-1), mustYMD(t, 19691231).AsEpochDay())
+	assert.Equal(t, int64(19542), mustYMD(t, 20230704).AsEpochDay())
+	assert.Equal(t, mustYMD(t, 20231231).AsEpochDay()+1, mustYMD(t, 20240101).AsEpochDay(), "dense across years")
+
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	assert.Equal(t, int64(19542), mustYMDIn(t, 20230704, tokyo).AsEpochDay(), "independent of location")
+	assert.Equal(t, YMDToTime(20230704, time.UTC).Unix()/86400, mustYMDIn(t, 20230704, tokyo).AsEpochDay())
+
+	ymdFlag, err := NewYMDFlagFromEpochDay(0, tokyo)
+	assert.NoError(t, err)
+	assert.Equal(t, 19700101, ymdFlag.GetYMD())
+	assert.Equal(t, tokyo, ymdFlag.Location())
+
+	for _, yyyymmdd := range []int{10101, 16001231, 19000301, 20000229, 20240229, 99991231} {
+		ymdFlag, err = NewYMDFlagFromEpochDay(mustYMD(t, yyyymmdd).AsEpochDay(), nil)
+		assert.NoError(t, err, "%d", yyyymmdd)
+		assert.Equal(t, yyyymmdd, ymdFlag.GetYMD(), "round-trips %d", yyyymmdd)
+	}
+	for _, n := range []int64{mustYMD(t, 10101).AsEpochDay() - 1, mustYMD(t, 99991231).AsEpochDay() + 1} {
+		_, err = NewYMDFlagFromEpochDay(n, nil)
+		assert.ErrorIs(t, err, ErrOutOfRange, "%d", n)
+	}
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, int64(19542), zero.AsEpochDay(), "nil resolves to today")
+	assert.True(t, zero.IsZero())
+}
+
+func TestDaysInMonth(t *testing.T) {
+	assert.Equal(t, 31, mustYMD(t, 20230704).DaysInMonth())
+	assert.Equal(t, 30, mustYMD(t, 20230430).DaysInMonth())
+	assert.Equal(t, 29, mustYMD(t, 20240201).DaysInMonth(), "leap year February")
+	assert.Equal(t, 28, mustYMD(t, 20230201).DaysInMonth(), "non-leap year February")
+	assert.Equal(t, 28, mustYMD(t, 19000215).DaysInMonth(), "1900 is a century")
+	assert.Equal(t, 29, mustYMD(t, 20000215).DaysInMonth(), "2000 is a fourth century")
+
+	setNow(t, time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 28, mustYMDIn(t, 0, time.UTC).DaysInMonth(), "nil is today")
+}
+
+func TestIsLeapYear(t *testing.T) {
+	assert.True(t, IsLeapYear(2024))
+	assert.False(t, IsLeapYear(2023))
+	assert.False(t, IsLeapYear(1900), "century")
+	assert.False(t, IsLeapYear(2100), "century")
+	assert.True(t, IsLeapYear(2000), "fourth century")
+	assert.True(t, IsLeapYear(1600), "fourth century")
+}
+
+func TestYearHasLeapDay(t *testing.T) {
+	assert.True(t, mustYMD(t, 20240101).YearHasLeapDay(), "2024 before Feb 29")
+	assert.True(t, mustYMD(t, 20241231).YearHasLeapDay(), "2024 after Feb 29")
+	assert.False(t, mustYMD(t, 20230704).YearHasLeapDay(), "2023")
+	assert.False(t, mustYMD(t, 21000301).YearHasLeapDay(), "2100 is a century")
+	assert.True(t, mustYMD(t, 20000301).YearHasLeapDay(), "2000 is a fourth century")
+}
+
+func TestGroupByWeek(t *testing.T) {
+	// Sun Jul 2 2023 through Tue Jul 11 2023
+	flags := []YMDFlag{
+		mustYMD(t, 20230711),
+		mustYMD(t, 20230702),
+		mustYMD(t, 20230703),
+		mustYMD(t, 20230709),
+		mustYMD(t, 20230708),
+		mustYMD(t, 20230710),
+	}
+
+	groups := GroupByWeek(flags, time.Monday)
+	assert.Equal(t, map[int][]int{
+		20230626: {20230702},
+		20230703: {20230703, 20230709, 20230708},
+		20230710: {20230711, 20230710},
+	}, groupInts(groups))
+
+	groups = GroupByWeek(flags, time.Sunday)
+	assert.Equal(t, map[int][]int{
+		20230702: {20230702, 20230703, 20230708},
+		20230709: {20230711, 20230709, 20230710},
+	}, groupInts(groups))
+
+	assert.Empty(t, GroupByWeek(nil, time.Monday))
+}
+
+func groupInts(groups map[int][]YMDFlag) map[int][]int {
+	result := make(map[int][]int, len(groups))
+	for key, flags := range groups {
+		result[key] = ymdInts(flags)
+	}
+	return result
+}
+
+func TestAgeInDays(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 10, mustYMDIn(t, 20230624, time.UTC).AgeInDays(), "10 days ago")
+	assert.Equal(t, 0, mustYMDIn(t, 20230704, time.UTC).AgeInDays(), "today")
+	assert.Equal(t, -3, mustYMDIn(t, 20230707, time.UTC).AgeInDays(), "future")
+	assert.Equal(t, 0, YMDFlag{}.AgeInDays(), "nil is today")
+	assert.Equal(t, 365, mustYMDIn(t, 20220704, time.UTC).AgeInDays(), "a year ago")
+
+	// it is already Jul 5 in UTC+14
+	assert.Equal(t, 1, mustYMDIn(t, 20230704, time.FixedZone("UTC+14", 14*60*60)).AgeInDays(), "today in the flag's location")
+}
+
+func TestIsTodayPastFuture(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	today, past, future := mustYMDIn(t, 20230704, time.UTC), mustYMDIn(t, 20230703, time.UTC), mustYMDIn(t, 20230705, time.UTC)
+	assert.True(t, today.IsToday())
+	assert.False(t, today.IsPast())
+	assert.False(t, today.IsFuture())
+	assert.False(t, past.IsToday())
+	assert.True(t, past.IsPast())
+	assert.False(t, past.IsFuture())
+	assert.False(t, future.IsToday())
+	assert.False(t, future.IsPast())
+	assert.True(t, future.IsFuture())
+
+	var zero YMDFlag
+	assert.True(t, zero.IsToday(), "nil is today")
+	assert.False(t, zero.IsPast())
+	assert.False(t, zero.IsFuture())
+	assert.True(t, zero.IsZero(), "not mutated")
+
+	// it is already Jul 5 in UTC+14 and still Jul 4 in UTC-12
+	east := mustYMDIn(t, 20230705, time.FixedZone("UTC+14", 14*60*60))
+	assert.True(t, east.IsToday(), "today in the flag's location")
+	assert.True(t, mustYMDIn(t, 20230704, time.FixedZone("UTC-12", -12*60*60)).IsToday(), "today in the flag's location")
+	assert.True(t, mustYMDIn(t, 20230704, east.Location()).IsPast(), "past in the flag's location")
+}
+
+func TestWeekday(t *testing.T) {
+	cases := map[int]time.Weekday{
+		20230704: time.Tuesday,
+		20230701: time.Saturday,
+		20230702: time.Sunday,
+		20240229: time.Thursday,
+		20000101: time.Saturday,
+	}
+	for yyyymmdd, weekday := range cases {
+		ymd := mustYMD(t, yyyymmdd)
+		assert.Equal(t, weekday, ymd.Weekday(), "%d", yyyymmdd)
+		assert.Equal(t, ymd.AsTime().Weekday(), ymd.Weekday(), "matches AsTime %d", yyyymmdd)
+		assert.Equal(t, weekday == time.Saturday || weekday == time.Sunday, ymd.IsWeekend(), "%d", yyyymmdd)
+	}
+
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymd := mustYMDIn(t, 20230704, loc)
+	assert.Equal(t, ymd.AsTime().Weekday(), ymd.Weekday(), "location does not change the weekday")
+
+	// Cuba springs forward from 00:00, so AsTime on Sun Mar 12 2023 is 23:00 on Saturday
+	havana := mustYMDIn(t, 20230312, mustLoadLocation(t, "America/Havana"))
+	assert.Equal(t, time.Sunday, havana.Weekday(), "weekday of the civil date")
+	assert.Equal(t, time.Saturday, havana.AsTime().Weekday())
+}
+
+func TestDaysUntilWeekday(t *testing.T) {
+	loc := mustLoadLocation(t, "America/Chicago")
+	tuesday := mustYMDIn(t, 20230704, loc)
+	assert.Equal(t, 6, tuesday.DaysUntilWeekday(time.Monday), "to the next Monday")
+	assert.Equal(t, 0, tuesday.DaysUntilWeekday(time.Tuesday), "same Tuesday")
+	assert.Equal(t, 3, tuesday.DaysUntilWeekday(time.Friday))
+	assert.Equal(t, 5, tuesday.DaysUntilWeekday(time.Sunday))
+
+	next := tuesday.NextWeekday(time.Monday)
+	assert.Equal(t, 20230710, next.GetYMD())
+	assert.Equal(t, time.Monday, next.Weekday())
+	assert.Equal(t, loc, next.Location())
+	assert.Equal(t, tuesday, tuesday.NextWeekday(time.Tuesday), "same Tuesday")
+	assert.Equal(t, 20230707, tuesday.NextWeekday(time.Friday).GetYMD())
+	assert.Equal(t, 20240101, mustYMD(t, 20231228).NextWeekday(time.Monday).GetYMD(), "across the year boundary")
+
+	prev := tuesday.PrevWeekday(time.Monday)
+	assert.Equal(t, 20230703, prev.GetYMD())
+	assert.Equal(t, loc, prev.Location())
+	assert.Equal(t, tuesday, tuesday.PrevWeekday(time.Tuesday), "same Tuesday")
+	assert.Equal(t, 20230628, tuesday.PrevWeekday(time.Wednesday).GetYMD(), "across the month boundary")
+	assert.Equal(t, 20230704, tuesday.GetYMD(), "receiver is unchanged")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, 3, zero.DaysUntilWeekday(time.Friday), "nil is today")
+	assert.Equal(t, 20230707, zero.NextWeekday(time.Friday).GetYMD())
+	assert.True(t, zero.IsZero())
+}
+
+func TestISOWeek(t *testing.T) {
+	cases := []struct {
+		yyyymmdd int
+		year     int
+		week     int
+		str      string
+	}{
+		{20230101, 2022, 52, "2022-W52"}, // Sunday belongs to the previous ISO year
+		{20210104, 2021, 1, "2021-W01"},  // first Monday of 2021
+		{20210103, 2020, 53, "2020-W53"}, // 2020 has 53 ISO weeks
+		{20230704, 2023, 27, "2023-W27"},
+		{20241230, 2025, 1, "2025-W01"}, // late December belongs to the next ISO year
+	}
+	for _, c := range cases {
+		year, week := mustYMD(t, c.yyyymmdd).ISOWeek()
+		assert.Equal(t, c.year, year, "%d", c.yyyymmdd)
+		assert.Equal(t, c.week, week, "%d", c.yyyymmdd)
+		assert.Equal(t, c.str, mustYMD(t, c.yyyymmdd).AsISOWeekString(), "%d", c.yyyymmdd)
+	}
+}
+
+func TestQuarter(t *testing.T) {
+	quarters := map[int]int{20230101: 1, 20230331: 1, 20230401: 2, 20230704: 3, 20230930: 3, 20231001: 4, 20231231: 4}
+	for yyyymmdd, quarter := range quarters {
+		assert.Equal(t, quarter, mustYMD(t, yyyymmdd).Quarter(), "%d", yyyymmdd)
+		assert.Equal(t, quarter, mustYMD(t, yyyymmdd).FiscalQuarter(time.January), "January start is calendar %d", yyyymmdd)
+		assert.Equal(t, 2023, mustYMD(t, yyyymmdd).FiscalYear(time.January), "January start is calendar %d", yyyymmdd)
+	}
+
+	// fiscal year starting in April
+	cases := []struct {
+		yyyymmdd int
+		quarter  int
+		year     int
+	}{
+		{20230401, 1, 2023},
+		{20230630, 1, 2023},
+		{20230704, 2, 2023},
+		{20231231, 3, 2023},
+		{20240115, 4, 2023}, // January is in Q4 of the prior fiscal year
+		{20240331, 4, 2023},
+		{20240401, 1, 2024},
+	}
+	for _, c := range cases {
+		ymd := mustYMD(t, c.yyyymmdd)
+		assert.Equal(t, c.quarter, ymd.FiscalQuarter(time.April), "%d", c.yyyymmdd)
+		assert.Equal(t, c.year, ymd.FiscalYear(time.April), "%d", c.yyyymmdd)
+	}
+
+	// fiscal year starting in October
+	assert.Equal(t, 1, mustYMD(t, 20231001).FiscalQuarter(time.October))
+	assert.Equal(t, 4, mustYMD(t, 20230930).FiscalQuarter(time.October))
+	assert.Equal(t, 2022, mustYMD(t, 20230930).FiscalYear(time.October))
+}
+
+func TestIsQuarterStartEnd(t *testing.T) {
+	assert.True(t, mustYMD(t, 20230101).IsQuarterStart())
+	assert.False(t, mustYMD(t, 20230101).IsQuarterEnd())
+	assert.True(t, mustYMD(t, 20230331).IsQuarterEnd())
+	assert.False(t, mustYMD(t, 20230331).IsQuarterStart())
+	assert.True(t, mustYMD(t, 20231001).IsQuarterStart())
+	assert.True(t, mustYMD(t, 20231231).IsQuarterEnd())
+	assert.False(t, mustYMD(t, 20230215).IsQuarterStart(), "mid quarter")
+	assert.False(t, mustYMD(t, 20230215).IsQuarterEnd(), "mid quarter")
+	assert.False(t, mustYMD(t, 20230201).IsQuarterStart(), "month start is not quarter start")
+}
+
+func TestMonthEndsBetween(t *testing.T) {
+	result := MonthEndsBetween(mustYMD(t, 20240115), mustYMD(t, 20240430))
+	assert.Equal(t, []int{20240131, 20240229, 20240331, 20240430}, ymdInts(result), "leap February")
+
+	result = MonthEndsBetween(mustYMD(t, 20221201), mustYMD(t, 20230315))
+	assert.Equal(t, []int{20221231, 20230131, 20230228}, ymdInts(result), "normal February across a year boundary")
+
+	result = MonthEndsBetween(mustYMD(t, 20230228), mustYMD(t, 20230228))
+	assert.Equal(t, []int{20230228}, ymdInts(result), "single month end day")
+
+	assert.Empty(t, MonthEndsBetween(mustYMD(t, 20230301), mustYMD(t, 20230330)), "no month end")
+	assert.Empty(t, MonthEndsBetween(mustYMD(t, 20230430), mustYMD(t, 20230101)), "reversed")
+}
@@ -0,0 +1,119 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"sort"
+)
+
+// Comparisons are of calendar dates, using the integral `yyyymmdd` value, and ignore location:
+// two YMDFlags for the same calendar date in different locations are Equal.
+// Nil YMDFlags are not resolved to today; a nil YMDFlag is before every set date.
+
+// Before returns true if the YMDFlag's calendar date is before `other`'s, ignoring location.
+// A nil YMDFlag is before every set date.
+func (ymd YMDFlag) Before(other YMDFlag) bool {
+	return ymd.yyyymmdd < other.yyyymmdd
+}
+
+// After returns true if the YMDFlag's calendar date is after `other`'s, ignoring location.
+// A nil YMDFlag is before every set date.
+func (ymd YMDFlag) After(other YMDFlag) bool {
+	return ymd.yyyymmdd > other.yyyymmdd
+}
+
+// Equal returns true if the YMDFlag's calendar date is the same as `other`'s, ignoring location.
+// Two nil YMDFlags are Equal, but a nil YMDFlag is not Equal to a set date for today.
+// Use Equal when comparing dates, such as business days or file partitions; use EqualStrict when
+// the date is tied to its location, such as when it will be converted to a time.Time.
+func (ymd YMDFlag) Equal(other YMDFlag) bool {
+	return ymd.yyyymmdd == other.yyyymmdd
+}
+
+// Compare returns -1 if the YMDFlag's calendar date is before `other`'s, 1 if it is after, and 0 if they are Equal,
+// ignoring location, so that `slices.SortFunc(flags, YMDFlag.Compare)` sorts by date.
+// A nil YMDFlag is before every set date, and is not resolved to today.
+func (ymd YMDFlag) Compare(other YMDFlag) int {
+	switch {
+	case ymd.yyyymmdd < other.yyyymmdd:
+		return -1
+	case ymd.yyyymmdd > other.yyyymmdd:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EqualStrict returns true if the YMDFlag is Equal to `other` and their locations have the same name.
+// Locations are compared by name rather than by pointer, and a nil location is "Local",
+// so EqualStrict matches comparing LocKeys.  Nil YMDFlags are not resolved to today.
+func (ymd YMDFlag) EqualStrict(other YMDFlag) bool {
+	return ymd.Equal(other) && ymd.CanonicalLocation().String() == other.CanonicalLocation().String()
+}
+
+// Key returns the integral `yyyymmdd`, for use as a map key when location is irrelevant.
+// YMDFlags themselves are comparable, but flags for the same date with different *time.Location pointers,
+// even for the same zone, are distinct map keys.  A nil YMDFlag is not resolved to today, so its Key is 0.
+func (ymd YMDFlag) Key() int {
+	return ymd.yyyymmdd
+}
+
+// LocKey returns `"YYYYMMDD@Location"`, for example `"20230704@America/New_York"`, for use as a map key
+// when location matters.  Locations are compared by name, and a nil location is "Local".
+// A nil YMDFlag is not resolved to today, so its date is empty.
+func (ymd YMDFlag) LocKey() string {
+	return ymd.AsYMDString() + "@" + ymd.CanonicalLocation().String()
+}
+
+// Min returns the earliest of the `flags`, keeping its location, or a nil YMDFlag if there are none.
+// Ties are won by the first such flag.  As with Before, a nil YMDFlag is earlier than every set date.
+func Min(flags ...YMDFlag) YMDFlag {
+	var result YMDFlag
+	for i, ymd := range flags {
+		if i == 0 || ymd.Before(result) {
+			result = ymd
+		}
+	}
+	return result
+}
+
+// Max returns the latest of the `flags`, keeping its location, or a nil YMDFlag if there are none.
+// Ties are won by the first such flag.  As with After, a nil YMDFlag is earlier than every set date.
+func Max(flags ...YMDFlag) YMDFlag {
+	var result YMDFlag
+	for i, ymd := range flags {
+		if i == 0 || ymd.After(result) {
+			result = ymd
+		}
+	}
+	return result
+}
+
+// Clamp returns the YMDFlag bounded to the inclusive interval from `min` to `max`, with the receiver's location.
+// Dates before `min` become `min`'s date, and dates after `max` become `max`'s date.
+// A nil `min` or `max` is unbounded on that side, as for an unset flag, rather than resolved to today.
+// If `min` is after `max`, then `min` takes precedence and the result always has `min`'s date.
+// Comparisons are on calendar dates, without resolving nil YMDFlags to today.
+func (ymd YMDFlag) Clamp(min, max YMDFlag) YMDFlag {
+	if !max.IsZero() && ymd.After(max) {
+		ymd.yyyymmdd = max.yyyymmdd
+	}
+	if !min.IsZero() && ymd.Before(min) {
+		ymd.yyyymmdd = min.yyyymmdd
+	}
+	return ymd
+}
+
+// ByDate implements sort.Interface for a slice of YMDFlags, ordering them by calendar date with Before.
+// Nil YMDFlags sort first and are not resolved to today.
+type ByDate []YMDFlag
+
+func (s ByDate) Len() int           { return len(s) }
+func (s ByDate) Less(i, j int) bool { return s[i].Before(s[j]) }
+func (s ByDate) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// SortYMDFlags sorts the slice in place by calendar date, keeping flags for the same date in their original order.
+// Nil YMDFlags sort first and are not resolved to today.
+func SortYMDFlags(s []YMDFlag) {
+	sort.Stable(ByDate(s))
+}
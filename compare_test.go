@@ -0,0 +1,173 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeforeAfterEqual(t *testing.T) {
+	early, late := mustYMD(t, 20230704), mustYMD(t, 20230705)
+	assert.True(t, early.Before(late))
+	assert.False(t, late.Before(early))
+	assert.False(t, early.Before(early))
+	assert.True(t, late.After(early))
+	assert.False(t, early.After(late))
+	assert.False(t, early.After(early))
+	assert.True(t, early.Equal(early))
+	assert.False(t, early.Equal(late))
+
+	// same calendar date in different locations
+	east := mustYMDIn(t, 20230704, time.FixedZone("UTC+14", 14*60*60))
+	west := mustYMDIn(t, 20230704, time.FixedZone("UTC-12", -12*60*60))
+	assert.True(t, east.Equal(west), "location is ignored")
+	assert.False(t, east.Before(west))
+	assert.False(t, east.After(west))
+
+	// nil is before every set date and is not resolved
+	var zero YMDFlag
+	today := NewYMDFlag(time.Now())
+	assert.True(t, zero.Before(today))
+	assert.True(t, today.After(zero))
+	assert.False(t, zero.Equal(today))
+	assert.True(t, zero.Equal(YMDFlag{}))
+	assert.True(t, zero.IsZero(), "not mutated")
+}
+
+func TestMinMax(t *testing.T) {
+	east := time.FixedZone("UTC+14", 14*60*60)
+	west := time.FixedZone("UTC-12", -12*60*60)
+	flags := []YMDFlag{mustYMDIn(t, 20230704, east), mustYMDIn(t, 20221225, west), mustYMD(t, 20240229), mustYMDIn(t, 20221225, east)}
+
+	assert.Equal(t, mustYMDIn(t, 20221225, west), Min(flags...), "first of the ties keeps its location")
+	assert.Equal(t, mustYMD(t, 20240229), Max(flags...))
+	assert.Equal(t, east, Max(flags[0], mustYMDIn(t, 20230704, west)).Location(), "first of the ties keeps its location")
+
+	single := mustYMDIn(t, 20230704, east)
+	assert.Equal(t, single, Min(single))
+	assert.Equal(t, single, Max(single))
+
+	assert.True(t, Min().IsZero(), "no flags")
+	assert.True(t, Max().IsZero(), "no flags")
+	assert.True(t, Min(single, YMDFlag{}).IsZero(), "nil is earliest")
+	assert.Equal(t, single, Max(YMDFlag{}, single))
+}
+
+func TestClamp(t *testing.T) {
+	east := time.FixedZone("UTC+14", 14*60*60)
+	min, max := mustYMD(t, 20230701), mustYMD(t, 20230731)
+
+	clamped := mustYMDIn(t, 20230615, east).Clamp(min, max)
+	assert.Equal(t, 20230701, clamped.GetYMD(), "below range")
+	assert.Equal(t, east, clamped.Location(), "location follows the receiver")
+	assert.Equal(t, 20230715, mustYMD(t, 20230715).Clamp(min, max).GetYMD(), "in range")
+	assert.Equal(t, 20230701, mustYMD(t, 20230701).Clamp(min, max).GetYMD(), "at min")
+	assert.Equal(t, 20230731, mustYMD(t, 20230731).Clamp(min, max).GetYMD(), "at max")
+	clamped = mustYMDIn(t, 20240101, east).Clamp(min, max)
+	assert.Equal(t, 20230731, clamped.GetYMD(), "above range")
+	assert.Equal(t, east, clamped.Location(), "location follows the receiver")
+
+	for _, yyyymmdd := range []int{20230615, 20230715, 20240101} {
+		assert.Equal(t, 20230731, mustYMD(t, yyyymmdd).Clamp(max, min).GetYMD(), "inverted bounds give min for %d", yyyymmdd)
+	}
+
+	// nil bounds, such as unset flags, are unbounded
+	var unset YMDFlag
+	assert.Equal(t, 20240101, mustYMD(t, 20240101).Clamp(min, unset).GetYMD(), "nil max")
+	assert.Equal(t, 20230701, mustYMD(t, 20230615).Clamp(min, unset).GetYMD(), "nil max keeps min")
+	assert.Equal(t, 20230615, mustYMD(t, 20230615).Clamp(unset, max).GetYMD(), "nil min")
+	assert.Equal(t, 20230731, mustYMD(t, 20240101).Clamp(unset, max).GetYMD(), "nil min keeps max")
+	assert.Equal(t, 20230615, mustYMD(t, 20230615).Clamp(unset, unset).GetYMD(), "both nil")
+}
+
+func TestSortYMDFlags(t *testing.T) {
+	east := time.FixedZone("UTC+14", 14*60*60)
+	flags := []YMDFlag{
+		mustYMD(t, 20230704), {}, mustYMD(t, 20240229), mustYMDIn(t, 20221225, east),
+		mustYMD(t, 20221225), mustYMDIn(t, 0, east), mustYMD(t, 20230101),
+	}
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	SortYMDFlags(flags)
+	assert.Equal(t, []int{0, 0, 20221225, 20221225, 20230101, 20230704, 20240229}, ymdInts(flags), "zeros first and not resolved")
+	assert.Equal(t, east, flags[1].Location(), "stable")
+	assert.Equal(t, east, flags[2].Location(), "stable")
+	assert.Nil(t, flags[3].Location(), "stable")
+
+	byDate := ByDate{mustYMD(t, 20230103), mustYMD(t, 20230101), mustYMD(t, 20230102)}
+	sort.Sort(byDate)
+	assert.True(t, sort.IsSorted(byDate))
+	assert.Equal(t, []int{20230101, 20230102, 20230103}, ymdInts(byDate))
+
+	SortYMDFlags(nil)
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, -1, mustYMD(t, 20230703).Compare(mustYMD(t, 20230704)))
+	assert.Equal(t, 1, mustYMD(t, 20230705).Compare(mustYMD(t, 20230704)))
+	assert.Equal(t, 0, mustYMD(t, 20230704).Compare(mustYMDIn(t, 20230704, time.FixedZone("UTC+14", 14*60*60))), "location is ignored")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, -1, zero.Compare(mustYMD(t, 10101)), "nil is smallest")
+	assert.Equal(t, 0, zero.Compare(YMDFlag{}))
+	assert.True(t, zero.IsZero(), "not resolved to today")
+
+	expected := []int{0, 0, 20221225, 20230101, 20230704, 20230705, 20240229, 20241231}
+	flags := make([]YMDFlag, len(expected))
+	for i, yyyymmdd := range expected {
+		flags[i] = mustYMDIn(t, yyyymmdd, time.UTC)
+	}
+	rng := rand.New(rand.NewSource(42))
+	rng.Shuffle(len(flags), func(i, j int) { flags[i], flags[j] = flags[j], flags[i] })
+	slices.SortFunc(flags, YMDFlag.Compare)
+	assert.Equal(t, expected, ymdInts(flags))
+	assert.True(t, slices.IsSortedFunc(flags, YMDFlag.Compare))
+}
+
+func TestEqualStrict(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+
+	// same date in different zones
+	a, b := mustYMDIn(t, 20230704, newYork), mustYMDIn(t, 20230704, tokyo)
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.EqualStrict(b))
+
+	// identical flags, and separately loaded locations with the same name
+	assert.True(t, a.Equal(a))
+	assert.True(t, a.EqualStrict(a))
+	assert.True(t, a.EqualStrict(mustYMDIn(t, 20230704, mustLoadLocation(t, "America/New_York"))))
+
+	assert.True(t, mustYMD(t, 20230704).EqualStrict(mustYMDIn(t, 20230704, time.Local)), "nil location is Local")
+	assert.False(t, a.EqualStrict(mustYMDIn(t, 20230705, newYork)), "different dates")
+	assert.True(t, mustYMDIn(t, 0, tokyo).EqualStrict(mustYMDIn(t, 0, tokyo)), "nil dates")
+	assert.False(t, mustYMDIn(t, 0, tokyo).EqualStrict(mustYMDIn(t, 0, newYork)))
+	assert.Equal(t, a.LocKey() == b.LocKey(), a.EqualStrict(b))
+}
+
+func TestKey(t *testing.T) {
+	// separately loaded locations are distinct pointers
+	first, second := mustLoadLocation(t, "America/New_York"), mustLoadLocation(t, "America/New_York")
+	a, b := mustYMDIn(t, 20230704, first), mustYMDIn(t, 20230704, second)
+	assert.NotSame(t, first, second)
+
+	byFlag := map[YMDFlag]bool{a: true, b: true, mustYMD(t, 20230704): true}
+	assert.Len(t, byFlag, 3, "flags are distinct keys")
+	byKey := map[int]bool{a.Key(): true, b.Key(): true, mustYMD(t, 20230704).Key(): true}
+	assert.Len(t, byKey, 1, "Key collapses the same date")
+	assert.Equal(t, 20230704, a.Key())
+
+	byLocKey := map[string]bool{a.LocKey(): true, b.LocKey(): true, mustYMD(t, 20230704).LocKey(): true, mustYMDIn(t, 20230704, time.Local).LocKey(): true}
+	assert.Len(t, byLocKey, 2, "LocKey collapses the same date and location name")
+	assert.Equal(t, "20230704@America/New_York", a.LocKey())
+	assert.Equal(t, "20230704@Local", mustYMD(t, 20230704).LocKey())
+	assert.Equal(t, "@UTC", mustYMDIn(t, 0, time.UTC).LocKey())
+	assert.Equal(t, 0, YMDFlag{}.Key())
+}
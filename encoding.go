@@ -0,0 +1,280 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// JSON
+
+// MarshalJSON implements the json.Marshaler interface.
+// The YMDFlag is encoded as a string `"YYYYMMDD"`, or `null` if the YMDFlag is nil.
+// A nil YMDFlag is not resolved to today.  The location is not encoded.
+func (ymd YMDFlag) MarshalJSON() ([]byte, error) {
+	if ymd.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ymd.AsYMDString())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts a string `"YYYYMMDD"`, a number `YYYYMMDD`, or `null`.
+// A string is parsed as Parse does, except that relative dates such as `"yesterday"` are never accepted.
+// Null, an empty string, and `0` result in a nil YMDFlag.  The YMDFlag's location is preserved.
+func (ymd *YMDFlag) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		ymd.yyyymmdd = 0
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		return ymd.set(str, false)
+	}
+	var yyyymmdd int
+	if err := json.Unmarshal(data, &yyyymmdd); err != nil {
+		return fmt.Errorf("expect JSON string or number of format YYYYMMDD %w", err)
+	}
+	if err := ValidateYMD(yyyymmdd); err != nil {
+		return err
+	}
+	ymd.yyyymmdd = yyyymmdd
+	return nil
+}
+
+// YMDNumberJSON is a YMDFlag which is encoded in JSON as a number `YYYYMMDD` rather than a string.
+// Use it as a struct field type for consumers which expect numeric dates.
+// It unmarshals exactly as YMDFlag does, accepting a string, a number, or `null`.
+type YMDNumberJSON struct {
+	YMDFlag
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The date is encoded as a number `YYYYMMDD`, or `null` if the YMDFlag is nil.
+// A nil YMDFlag is not resolved to today.  The location is not encoded.
+func (ymd YMDNumberJSON) MarshalJSON() ([]byte, error) {
+	if ymd.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ymd.yyyymmdd)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Text
+
+// MarshalText implements the encoding.TextMarshaler interface, returning `YYYYMMDD`.
+// A nil YMDFlag marshals as empty text, and is not resolved to today.  The location is not encoded.
+func (ymd YMDFlag) MarshalText() ([]byte, error) {
+	return []byte(ymd.AsYMDString()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, parsing the text as Parse does,
+// except that relative dates such as `yesterday` are never accepted.  The YMDFlag's location is preserved.
+func (ymd *YMDFlag) UnmarshalText(text []byte) error {
+	return ymd.set(string(text), false)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// database/sql
+
+// Value implements the driver.Valuer interface, returning the integral `yyyymmdd` as an int64.
+// A nil YMDFlag is stored as NULL, rather than 0, and is not resolved to today.
+func (ymd YMDFlag) Value() (driver.Value, error) {
+	if ymd.IsZero() {
+		return nil, nil
+	}
+	return int64(ymd.yyyymmdd), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts integral `YYYYMMDD` values as int64, and `"YYYYMMDD"` as string or []byte.
+// A time.Time is converted with TimeToYMD in the YMDFlag's location, or in the time's own location if that is nil.
+// NULL results in a nil YMDFlag.  The YMDFlag's location is preserved.
+func (ymd *YMDFlag) Scan(src any) error {
+	var yyyymmdd int
+	switch v := src.(type) {
+	case nil:
+		yyyymmdd = 0
+	case int64:
+		if v < 0 || v > 99999999 {
+			return fmt.Errorf("failed to validate yyyymmdd %d: %w", v, ErrOutOfRange)
+		}
+		yyyymmdd = int(v)
+		if err := ValidateYMD(yyyymmdd); err != nil {
+			return err
+		}
+	case string:
+		var err error
+		if yyyymmdd, err = StringToYMD(v); err != nil {
+			return err
+		}
+	case []byte:
+		var err error
+		if yyyymmdd, err = StringToYMD(string(v)); err != nil {
+			return err
+		}
+	case time.Time:
+		if ymd.loc != nil {
+			v = v.In(ymd.loc)
+		}
+		var err error
+		if yyyymmdd, err = TimeToYMDChecked(v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot scan type %T into YMDFlag", src)
+	}
+	ymd.yyyymmdd = yyyymmdd
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Bytes
+
+// AsBytes returns the YMDFlag's integral `yyyymmdd` as 4 big-endian bytes, suitable for use as a key.
+// Because the encoding is fixed-width, the byte ordering of two encoded dates matches their chronological order.
+// A nil YMDFlag encodes as 4 zero bytes.  The location is not encoded.
+func (ymd YMDFlag) AsBytes() []byte {
+	return binary.BigEndian.AppendUint32(make([]byte, 0, 4), uint32(ymd.yyyymmdd))
+}
+
+// NewYMDFlagFromBytes creates a new YMDFlag in the given location from the 4 big-endian bytes produced by AsBytes.
+// Returns a non-nil error if the slice is not 4 bytes or the value is malformed.
+func NewYMDFlagFromBytes(b []byte, loc *time.Location) (YMDFlag, error) {
+	if len(b) != 4 {
+		return YMDFlag{}, fmt.Errorf("expect 4 bytes, got %d", len(b))
+	}
+	yyyymmdd := int(binary.BigEndian.Uint32(b))
+	if err := ValidateYMD(yyyymmdd); err != nil {
+		return YMDFlag{}, err
+	}
+	return YMDFlag{yyyymmdd: yyyymmdd, loc: loc}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// binary and gob
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It encodes the integral `yyyymmdd` as 4 big-endian bytes, followed by the uvarint-length-prefixed location name.
+// The fixed-width date prefix makes encoded values sort by date.
+// A nil location encodes as an empty name.  A nil YMDFlag is not resolved to today.
+func (ymd YMDFlag) MarshalBinary() ([]byte, error) {
+	name := ""
+	if ymd.loc != nil {
+		name = ymd.loc.String()
+	}
+	data := ymd.AsBytes()
+	data = binary.AppendUvarint(data, uint64(len(name)))
+	return append(data, name...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, decoding the output of MarshalBinary.
+// The location is resolved with `time.LoadLocation`, so "UTC" and "Local" are restored as `time.UTC` and `time.Local`,
+// and an empty name is restored as nil.  Locations without an IANA name, such as from `time.FixedZone`, fail to decode.
+func (ymd *YMDFlag) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("expect at least 4 bytes, got %d", len(data))
+	}
+	decoded, err := NewYMDFlagFromBytes(data[:4], nil)
+	if err != nil {
+		return err
+	}
+	length, n := binary.Uvarint(data[4:])
+	if n <= 0 || uint64(len(data)-4-n) != length {
+		return fmt.Errorf("malformed location name")
+	}
+	if name := string(data[4+n:]); name != "" {
+		if decoded.loc, err = time.LoadLocation(name); err != nil {
+			return fmt.Errorf("failed to load location %w", err)
+		}
+	}
+	*ymd = decoded
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, using the MarshalBinary format.
+func (ymd YMDFlag) GobEncode() ([]byte, error) {
+	return ymd.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface, using the UnmarshalBinary format.
+func (ymd *YMDFlag) GobDecode(data []byte) error {
+	return ymd.UnmarshalBinary(data)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// YMDRange
+
+// MarshalText implements the encoding.TextMarshaler interface, returning the range as `START..END`,
+// such as `20230101..20230131`, which is the form YMDRangeFlag accepts.  A nil endpoint is empty,
+// so a range through today is `20230101..`, and a range with both endpoints nil marshals as empty text.
+// Nil endpoints are not resolved to today.  Locations are not encoded.
+func (r YMDRange) MarshalText() ([]byte, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(r.Start.AsYMDString() + ".." + r.End.AsYMDString()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, parsing `START..END`,
+// or a single date as a one-day range.  Each endpoint is parsed as YMDFlag.UnmarshalText does,
+// without relative dates, keeping any location already set on it, and an empty endpoint is nil.
+// If either endpoint is invalid, or START is after END, the range is unchanged and an error is returned.
+func (r *YMDRange) UnmarshalText(text []byte) error {
+	return r.unmarshalText(string(text), false)
+}
+
+// unmarshalText is UnmarshalText, with relative endpoints accepted only if `relative` is true.
+func (r *YMDRange) unmarshalText(text string, relative bool) error {
+	startStr, endStr, found := strings.Cut(text, "..")
+	if !found {
+		endStr = startStr
+	}
+	parsed := *r
+	if err := parsed.Start.set(startStr, relative); err != nil {
+		return fmt.Errorf("invalid range start %w", err)
+	}
+	if err := parsed.End.set(endStr, relative); err != nil {
+		return fmt.Errorf("invalid range end %w", err)
+	}
+	if err := parsed.Validate(); err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The range is encoded as a string `"START..END"`, as MarshalText does, or `null` if both endpoints are nil.
+func (r YMDRange) MarshalJSON() ([]byte, error) {
+	if r.Start.IsZero() && r.End.IsZero() {
+		return []byte("null"), nil
+	}
+	text, _ := r.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting a string parsed as UnmarshalText does, or `null`.
+// Null results in both endpoints being nil.  Locations already set on the endpoints are preserved.
+func (r *YMDRange) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		r.Start.yyyymmdd, r.End.yyyymmdd = 0, 0
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("expect JSON string of format START..END %w", err)
+	}
+	return r.UnmarshalText([]byte(str))
+}
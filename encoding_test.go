@@ -0,0 +1,307 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsBytes(t *testing.T) {
+	loc := time.FixedZone("UTC+1", 60*60)
+	ymdFlag := mustYMDIn(t, 20230704, loc)
+
+	b := ymdFlag.AsBytes()
+	assert.Len(t, b, 4)
+	roundTrip, err := NewYMDFlagFromBytes(b, loc)
+	assert.NoError(t, err)
+	assert.Equal(t, ymdFlag, roundTrip)
+
+	assert.Equal(t, []byte{0, 0, 0, 0}, YMDFlag{}.AsBytes(), "nil flag")
+
+	dates := []int{19991231, 20000101, 20230704, 20230705, 20231231, 20240101}
+	for i := 1; i < len(dates); i++ {
+		prev, next := mustYMD(t, dates[i-1]).AsBytes(), mustYMD(t, dates[i]).AsBytes()
+		assert.Equal(t, -1, bytes.Compare(prev, next), "byte order matches date order for %d", dates[i])
+	}
+
+	_, err = NewYMDFlagFromBytes([]byte{1, 2, 3}, nil)
+	assert.Error(t, err, "wrong length")
+
+	_, err = NewYMDFlagFromBytes(mustYMD(t, 20230731).AsBytes()[:3], nil)
+	assert.Error(t, err, "truncated")
+
+	_, err = NewYMDFlagFromBytes([]byte{0xff, 0xff, 0xff, 0xff}, nil)
+	assert.Error(t, err, "malformed value")
+}
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Date YMDFlag  `json:"date"`
+		Ptr  *YMDFlag `json:"ptr,omitempty"`
+	}
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	in := config{Date: mustYMDIn(t, 20230704, loc)}
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"date":"20230704"}`, string(data))
+
+	out := config{Date: mustYMDIn(t, 0, loc)}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out, "round trip including the preset location")
+
+	// numeric form
+	var ymdFlag YMDFlag
+	ymdFlag.SetLocation(loc)
+	assert.NoError(t, json.Unmarshal([]byte(`20240229`), &ymdFlag))
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location())
+
+	// zero marshals deterministically and without resolving to today
+	var zero YMDFlag
+	data, err = json.Marshal(zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	assert.True(t, zero.IsZero())
+
+	for _, input := range []string{`null`, `""`, `0`} {
+		ymdFlag = mustYMD(t, 20230704)
+		assert.NoError(t, json.Unmarshal([]byte(input), &ymdFlag), input)
+		assert.True(t, ymdFlag.IsZero(), input)
+	}
+
+	for _, input := range []string{`"20230229"`, `20231301`, `"2023"`, `true`, `{}`, `2023.5`} {
+		assert.Error(t, json.Unmarshal([]byte(input), &ymdFlag), input)
+	}
+
+	// stored data decodes to the same date on any day, so relative dates are rejected
+	for _, input := range []string{`"today"`, `"yesterday"`, `"+7"`, `"-1"`} {
+		assert.ErrorIs(t, json.Unmarshal([]byte(input), &ymdFlag), ErrBadFormat, input)
+	}
+}
+
+func TestYMDNumberJSON(t *testing.T) {
+	type config struct {
+		String YMDFlag       `json:"string"`
+		Number YMDNumberJSON `json:"number"`
+	}
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	in := config{String: mustYMDIn(t, 20230704, loc), Number: YMDNumberJSON{mustYMDIn(t, 20240229, loc)}}
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"string":"20230704","number":20240229}`, string(data))
+
+	out := config{String: mustYMDIn(t, 0, loc), Number: YMDNumberJSON{mustYMDIn(t, 0, loc)}}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out, "round trip including the preset location")
+
+	// both output modes accept both input forms
+	for _, input := range []string{`{"string":20230704,"number":"20230704"}`, `{"string":"2023-07-04","number":20230704}`} {
+		out = config{}
+		assert.NoError(t, json.Unmarshal([]byte(input), &out), input)
+		assert.Equal(t, 20230704, out.String.GetYMD(), input)
+		assert.Equal(t, 20230704, out.Number.GetYMD(), input)
+	}
+
+	var zero YMDNumberJSON
+	data, err = json.Marshal(zero)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	assert.True(t, zero.IsZero())
+
+	number := YMDNumberJSON{mustYMD(t, 20230704)}
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &number))
+	assert.True(t, number.IsZero())
+	assert.Error(t, json.Unmarshal([]byte(`20230229`), &number))
+}
+
+func TestSQL(t *testing.T) {
+	value, err := mustYMD(t, 20230704).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20230704), value)
+
+	value, err = YMDFlag{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value, "nil flag is NULL")
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	sources := []any{
+		int64(20230704),
+		"20230704",
+		[]byte("20230704"),
+		time.Date(2023, time.July, 4, 12, 0, 0, 0, loc),
+		time.Date(2023, time.July, 5, 1, 0, 0, 0, time.UTC), // still Jul 4 in loc
+	}
+	for _, src := range sources {
+		ymdFlag := mustYMDIn(t, 0, loc)
+		assert.NoError(t, ymdFlag.Scan(src), "%T %v", src, src)
+		assert.Equal(t, 20230704, ymdFlag.GetYMD(), "%T %v", src, src)
+		assert.Equal(t, loc, ymdFlag.Location(), "location is preserved")
+	}
+
+	ymdFlag := mustYMD(t, 20230704)
+	assert.NoError(t, ymdFlag.Scan(nil))
+	assert.True(t, ymdFlag.IsZero(), "NULL is nil")
+
+	for _, src := range []any{int64(20230229), int64(-1), int64(1 << 40), "2023-07-04", []byte("hello"), 3.14} {
+		ymdFlag := mustYMD(t, 20230704)
+		assert.Error(t, ymdFlag.Scan(src), "%T %v", src, src)
+		assert.Equal(t, 20230704, ymdFlag.GetYMD(), "unchanged on error")
+	}
+}
+
+func TestText(t *testing.T) {
+	// a nil flag resolved to this today would marshal as 20230704 rather than empty text
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	text, err := mustYMD(t, 20230704).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "20230704", string(text))
+
+	var zero YMDFlag
+	text, err = zero.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(text), "nil flag is not resolved to today")
+	assert.True(t, zero.IsZero(), "nil flag is not mutated")
+
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	ymdFlag := mustYMDIn(t, 0, loc)
+	assert.NoError(t, ymdFlag.UnmarshalText([]byte("20240229")))
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location(), "location is preserved")
+
+	assert.NoError(t, ymdFlag.UnmarshalText(nil))
+	assert.True(t, ymdFlag.IsZero(), "empty text is nil")
+
+	assert.Error(t, ymdFlag.UnmarshalText([]byte("20230229")), "invalid date")
+	assert.ErrorIs(t, ymdFlag.UnmarshalText([]byte("yesterday")), ErrBadFormat, "relative dates are rejected")
+	assert.ErrorIs(t, ymdFlag.UnmarshalText([]byte("+7")), ErrBadFormat, "relative dates are rejected")
+}
+
+func TestGob(t *testing.T) {
+	type message struct {
+		Date  YMDFlag
+		Dates []YMDFlag
+	}
+	newYork := mustLoadLocation(t, "America/New_York")
+	in := message{
+		Date:  mustYMDIn(t, 20230704, newYork),
+		Dates: []YMDFlag{mustYMD(t, 20240229), mustYMDIn(t, 20230101, time.UTC), mustYMDIn(t, 20230102, time.Local), {}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(in))
+	var out message
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+
+	assert.Equal(t, 20230704, out.Date.GetYMD())
+	assert.Equal(t, "America/New_York", out.Date.Location().String())
+	assert.Equal(t, in.Date.AsTime(), out.Date.AsTime())
+	assert.Equal(t, in.Dates, out.Dates, "nil, UTC and Local locations round-trip exactly")
+
+	var ymdFlag YMDFlag
+	assert.Error(t, ymdFlag.GobDecode([]byte{1, 2}), "too short")
+	data, _ := mustYMDIn(t, 20230704, newYork).GobEncode()
+	assert.Error(t, ymdFlag.GobDecode(data[:len(data)-1]), "truncated name")
+	data, _ = mustYMDIn(t, 20230704, time.FixedZone("NOWHERE", 3600)).GobEncode()
+	assert.Error(t, ymdFlag.GobDecode(data), "unknown location")
+	assert.True(t, ymdFlag.IsZero(), "unchanged on error")
+}
+
+func TestBinary(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	for _, in := range []YMDFlag{mustYMDIn(t, 20230704, tokyo), mustYMDIn(t, 20230704, time.UTC), mustYMD(t, 20240229), {}} {
+		data, err := in.MarshalBinary()
+		assert.NoError(t, err)
+		var out YMDFlag
+		assert.NoError(t, out.UnmarshalBinary(data))
+		assert.Equal(t, in.GetYMD(), out.GetYMD())
+		assert.Equal(t, in.Location().String(), out.Location().String())
+	}
+
+	setNow(t, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	data, err := YMDFlag{}.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0}, data, "zero is not resolved to today")
+
+	// binary prefixes sort by date, regardless of location
+	earlier, _ := mustYMDIn(t, 20231231, tokyo).MarshalBinary()
+	later, _ := mustYMDIn(t, 20240101, time.UTC).MarshalBinary()
+	assert.Negative(t, bytes.Compare(earlier[:4], later[:4]))
+	assert.Negative(t, bytes.Compare(earlier, later))
+
+	var ymdFlag YMDFlag
+	assert.Error(t, ymdFlag.UnmarshalBinary(nil))
+	assert.Error(t, ymdFlag.UnmarshalBinary(append(later, 'x')), "trailing bytes")
+}
+
+func TestYMDRangeText(t *testing.T) {
+	r := mustRange(t, 20230101, 20230131)
+	text, err := r.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "20230101..20230131", string(text))
+
+	var out YMDRange
+	assert.NoError(t, out.UnmarshalText(text))
+	assert.Equal(t, r, out, "round trip")
+
+	open := YMDRange{Start: mustYMD(t, 20230101)}
+	text, err = open.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "20230101..", string(text), "nil end is not resolved")
+	text, err = YMDRange{}.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(text))
+
+	assert.NoError(t, out.UnmarshalText([]byte("2024-02-29")), "single date")
+	assert.Equal(t, mustRange(t, 20240229, 20240229), out)
+	assert.Error(t, out.UnmarshalText([]byte("20230131..20230101")), "inverted range")
+	assert.Error(t, out.UnmarshalText([]byte("20230101..20230230")), "invalid end")
+	assert.ErrorIs(t, out.UnmarshalText([]byte("-7..yesterday")), ErrBadFormat, "relative endpoints are rejected")
+	assert.Equal(t, mustRange(t, 20240229, 20240229), out, "unchanged on error")
+}
+
+func TestYMDRangeJSON(t *testing.T) {
+	type config struct {
+		Window YMDRange  `json:"window"`
+		Ptr    *YMDRange `json:"ptr,omitempty"`
+	}
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	in := config{Window: YMDRange{Start: mustYMDIn(t, 20230101, loc), End: mustYMDIn(t, 20230131, loc)}}
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"window":"20230101..20230131"}`, string(data))
+
+	out := config{Window: YMDRange{Start: mustYMDIn(t, 0, loc), End: mustYMDIn(t, 0, loc)}}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out, "round trip including the preset locations")
+
+	data, err = json.Marshal(YMDRange{})
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &out.Window))
+	assert.True(t, out.Window.Start.IsZero() && out.Window.End.IsZero())
+	assert.Equal(t, loc, out.Window.Start.Location(), "null keeps locations")
+
+	err = json.Unmarshal([]byte(`{"window":"20230131..20230101"}`), &out)
+	assert.ErrorContains(t, err, "after end", "inverted range")
+	for _, input := range []string{`"20230101..bad"`, `20230101`, `{}`, `true`} {
+		var r YMDRange
+		assert.Error(t, json.Unmarshal([]byte(input), &r), input)
+	}
+
+	// YMDRangeFlag encodes as its YMDRange
+	flag := YMDRangeFlag{mustRange(t, 20230101, 20230131)}
+	data, err = json.Marshal(flag)
+	assert.NoError(t, err)
+	assert.Equal(t, `"20230101..20230131"`, string(data))
+	assert.Equal(t, "20230101..20230131", flag.String())
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2023 Neomantra BV
+
+package main
+
+import (
+	"flag"
+
+	"github.com/neomantra/ymdflag"
+)
+
+func main() {
+	var ymd ymdflag.YMDFlag
+	flag.Var(&ymd, "date", "YYYYMMDD date; defaults to today in local time")
+	flag.Parse()
+	println("time of date:", ymd.AsTime().String())
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2023 Neomantra BV
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/neomantra/ymdflag"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	// accept dates from 2000-01-01 through today
+	min, _ := ymdflag.NewYMDFlagFromInt(20000101)
+	date, err := ymdflag.NewBoundedYMDFlag(min, ymdflag.YMDFlag{})
+	if err != nil {
+		panic(err)
+	}
+	pflag.VarP(date, "date", "d", "YYYYMMDD date from 20000101 through today; defaults to today in local time")
+	pflag.Parse()
+	fmt.Println("time of date:", date.AsTime().String())
+}
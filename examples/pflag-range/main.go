@@ -0,0 +1,24 @@
+// Copyright (c) 2023 Neomantra BV
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/neomantra/ymdflag"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	var dateRange ymdflag.YMDRangeFlag
+	pflag.VarP(&dateRange, "range", "r", "START..END dates, inclusive; a single date is a one-day range")
+	pflag.Parse()
+	if dateRange.String() == "" {
+		fmt.Fprintln(os.Stderr, "--range is required")
+		os.Exit(1)
+	}
+	for _, ymd := range dateRange.Days() {
+		fmt.Println("date:", ymd.AsYMDString())
+	}
+}
@@ -8,8 +8,7 @@ import (
 )
 
 func main() {
-	var ymd ymdflag.YMDFlag
-	pflag.VarP(&ymd, "date", "d", "YYYYMMDD date; defaults to today in local time")
+	ymd := ymdflag.AddFlag(pflag.CommandLine, "date", "d", "YYYYMMDD date; defaults to today in local time", nil)
 	pflag.Parse()
 	println("time of date:", ymd.AsTime().String())
 }
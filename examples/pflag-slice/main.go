@@ -0,0 +1,19 @@
+// Copyright (c) 2023 Neomantra BV
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/neomantra/ymdflag"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	var dates ymdflag.YMDFlagSlice
+	pflag.VarP(&dates, "date", "d", "YYYYMMDD date; may be repeated or comma-separated")
+	pflag.Parse()
+	for _, ymd := range dates {
+		fmt.Println("time of date:", ymd.AsTime().String())
+	}
+}
@@ -5,7 +5,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/neomantra/ymdflag"
 	"github.com/spf13/pflag"
@@ -25,14 +24,12 @@ func main() {
 		startDate = endDate
 	}
 
-	var startTime, endTime time.Time
-	if st, et := startDate.AsTime(), endDate.AsTime(); et.Equal(st) || et.After(st) {
-		startTime = time.Date(st.Year(), st.Month(), st.Day(), 0, 0, 0, 0, time.UTC)
-		endTime = time.Date(et.Year(), et.Month(), et.Day(), 23, 59, 59, 0, time.UTC)
-	} else {
+	if endDate.AsTime().Before(startDate.AsTime()) {
 		fmt.Fprint(os.Stderr, "--start must be before --end\n")
 		os.Exit(1)
 	}
+	startTime := startDate.UTC().AsStartOfDayTime()
+	endTime := endDate.UTC().AsEndOfDayTime()
 
 	fmt.Fprintf(os.Stdout, "startTime: %s   endTime: %s\n", startTime.String(), endTime.String())
 }
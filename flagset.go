@@ -0,0 +1,28 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// AddFlag creates a nil YMDFlag in location `loc`, registers it with the pflag FlagSet `fs`
+// with the given name, shorthand, and usage, and returns a pointer to it, like `pflag.StringP`.
+// An empty shorthand registers only the long name.  Use `pflag.CommandLine` for the default FlagSet.
+// The YMDFlag resolves to today in `loc` when accessed, unless the flag is set.
+func AddFlag(fs *pflag.FlagSet, name, shorthand, usage string, loc *time.Location) *YMDFlag {
+	ymd := &YMDFlag{loc: loc}
+	fs.VarP(ymd, name, shorthand, usage)
+	return ymd
+}
+
+// AddStdFlag is AddFlag for the standard library's flag FlagSet `fs`, which has no shorthands.
+// Use `flag.CommandLine` for the default FlagSet.
+func AddStdFlag(fs *flag.FlagSet, name, usage string, loc *time.Location) *YMDFlag {
+	ymd := &YMDFlag{loc: loc}
+	fs.Var(ymd, name, usage)
+	return ymd
+}
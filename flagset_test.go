@@ -0,0 +1,77 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFlag(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	start := AddFlag(fs, "start", "s", "YYYYMMDD start date", loc)
+	end := AddFlag(fs, "end", "", "YYYYMMDD end date", time.UTC)
+	assert.NoError(t, fs.Parse([]string{"-s", "20230704"}))
+
+	assert.Equal(t, 20230704, start.GetYMD())
+	assert.Equal(t, loc, start.Location(), "location is preserved by Set")
+	assert.True(t, end.IsZero(), "unset flag is nil")
+	assert.Equal(t, time.UTC, end.Location())
+	assert.Equal(t, "YMDFlag[America/New_York]", fs.Lookup("start").Value.Type())
+	assert.Equal(t, "YMDFlag[UTC]", fs.Lookup("end").Value.Type())
+	assert.Contains(t, fs.FlagUsages(), "--start YMDFlag[America/New_York]", "help shows the location")
+	assert.Equal(t, "YYYYMMDD end date", fs.Lookup("end").Usage)
+
+	assert.NoError(t, fs.Parse([]string{"--end", "2023-07-05"}))
+	assert.Equal(t, 20230705, end.GetYMD())
+	assert.Error(t, fs.Parse([]string{"--start", "2023"}))
+}
+
+func TestAddStdFlag(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Tokyo")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	date := AddStdFlag(fs, "date", "YYYYMMDD date", loc)
+	other := AddStdFlag(fs, "other", "YYYYMMDD date", nil)
+	assert.NoError(t, fs.Parse([]string{"-date", "20230704"}))
+
+	assert.Equal(t, 20230704, date.GetYMD())
+	assert.Equal(t, loc, date.Location(), "location is preserved by Set")
+	assert.True(t, other.IsZero(), "unset flag is nil")
+	assert.Error(t, fs.Parse([]string{"-date", "2023"}))
+}
+
+// TestStdlibFlag guards compatibility with the standard library's flag package,
+// which only uses the flag.Value methods, regardless of pflag-specific methods like Type.
+func TestStdlibFlag(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	date := mustYMDIn(t, 0, time.UTC)
+	unset := mustYMDIn(t, 0, time.UTC)
+	fs.Var(&date, "date", "YYYYMMDD date")
+	fs.Var(&unset, "unset", "YYYYMMDD date")
+	assert.Equal(t, "", fs.Lookup("date").DefValue, "zero default is empty")
+
+	assert.NoError(t, fs.Parse([]string{"-date", "20230704"}))
+	assert.Equal(t, 20230704, date.GetYMD())
+	assert.Equal(t, "20230704", fs.Lookup("date").Value.String())
+
+	// zero-value handling matches pflag: an unset flag stays nil until accessed, and resolves to today
+	assert.True(t, unset.IsZero())
+	assert.Equal(t, "", fs.Lookup("unset").Value.String())
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), unset.AsTime())
+
+	// an explicitly empty value is unset
+	assert.NoError(t, fs.Parse([]string{"-date="}))
+	assert.True(t, date.IsZero())
+	assert.Error(t, fs.Parse([]string{"-date", "20230229"}))
+}
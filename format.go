@@ -0,0 +1,132 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AsSQLDateLiteral returns the YMDFlag as a SQL DATE literal, for example `DATE '2023-07-04'`.
+// If the YMDFlag is nil, then `NULL` is returned.
+//
+// The literal contains only digits and dashes, but is intended for building trusted queries;
+// prefer bound parameters when the date originates from user input.
+func (ymd YMDFlag) AsSQLDateLiteral() string {
+	if ymd.IsZero() {
+		return "NULL"
+	}
+	year, month, day := ymd.AsYearMonthDay()
+	return fmt.Sprintf("DATE '%04d-%02d-%02d'", year, month, day)
+}
+
+// AsISOString returns the YMDFlag as an ISO 8601 / RFC 3339 full-date string `"YYYY-MM-DD"`, for example `"2023-07-04"`.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsISOString() string {
+	year, month, day := ymd.resolved().AsYearMonthDay()
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+}
+
+// HumanizeSince returns the YMDFlag's date relative to today in its location as an English phrase:
+// "today", "yesterday", "tomorrow", "N days ago", or "in N days".  Only days are used, never weeks or months.
+// A nil YMDFlag is "today".  Today is determined using NowFunc.
+func (ymd YMDFlag) HumanizeSince() string {
+	switch age := ymd.AgeInDays(); {
+	case age == 0:
+		return "today"
+	case age == 1:
+		return "yesterday"
+	case age == -1:
+		return "tomorrow"
+	case age > 0:
+		return fmt.Sprintf("%d days ago", age)
+	default:
+		return fmt.Sprintf("in %d days", -age)
+	}
+}
+
+// DayOrdinal returns the day of the month of the YMDFlag as an English ordinal, for example `4th` or `22nd`.
+// A nil YMDFlag is resolved to today.
+func (ymd YMDFlag) DayOrdinal() string {
+	_, _, day := ymd.resolved().AsYearMonthDay()
+	suffix := "th"
+	switch {
+	case day >= 11 && day <= 13:
+		// 11th, 12th, 13th
+	case day%10 == 1:
+		suffix = "st"
+	case day%10 == 2:
+		suffix = "nd"
+	case day%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
+
+// AsFormat returns the YMDFlag's midnight in its location, formatted with the given `time.Format` layout.
+// Time-of-day elements of the layout format as midnight.  A nil YMDFlag is resolved to today.
+func (ymd YMDFlag) AsFormat(layout string) string {
+	resolved := ymd.resolved()
+	return resolved.AsTime().Format(layout)
+}
+
+// AsDirPathDepth returns the leading `depth` levels of the YMDFlag's FormatDirPath `"YYYY/MM/DD"`,
+// using the given path separator: depth 1 gives `"YYYY"`, 2 gives `"YYYY/MM"`, and 3 gives `"YYYY/MM/DD"`.
+// Depth is clamped, so 0 or less gives the empty string and more than 3 gives `"YYYY/MM/DD"`.
+// If the YMDFlag is nil, then an empty string is returned.
+func (ymd YMDFlag) AsDirPathDepth(depth int, separator rune) string {
+	if ymd.IsZero() || depth <= 0 {
+		return ""
+	}
+	year, month, day := ymd.AsYearMonthDay()
+	switch depth {
+	case 1:
+		return fmt.Sprintf("%04d", year)
+	case 2:
+		return fmt.Sprintf("%04d%c%02d", year, separator, month)
+	default:
+		return fmt.Sprintf("%04d%c%02d%c%02d", year, separator, month, separator, day)
+	}
+}
+
+// AsPartitionPath returns the YMDFlag as a Hive-style partition path `"year=YYYY/month=MM/day=DD"`,
+// as used by data lakes like Athena and Spark.  If the YMDFlag is nil, then an empty string is returned.
+func (ymd YMDFlag) AsPartitionPath() string {
+	return ymd.AsPartitionPathSep('/')
+}
+
+// AsPartitionPathSep returns the YMDFlag as a Hive-style partition path using the given path separator,
+// for example `filepath.Separator`.  If the YMDFlag is nil, then an empty string is returned.
+func (ymd YMDFlag) AsPartitionPathSep(separator rune) string {
+	if ymd.IsZero() {
+		return ""
+	}
+	year, month, day := ymd.AsYearMonthDay()
+	return fmt.Sprintf("year=%04d%cmonth=%02d%cday=%02d", year, separator, month, separator, day)
+}
+
+// Format implements the fmt.Formatter interface, so YMDFlags may be rendered directly in format strings:
+//
+//	%d     the integral `YYYYMMDD`, for example 20230704, or 0 if nil
+//	%s %v  the string `"YYYYMMDD"`, as String returns, or empty if nil
+//	%+s %+v  the ISO 8601 string `"YYYY-MM-DD"`, or empty if nil
+//	%q     the double-quoted `"YYYYMMDD"`, or `"YYYY-MM-DD"` with `%+q`
+//
+// Width and other flags apply as they do for ints and strings.  Nil YMDFlags are not resolved to today.
+func (ymd YMDFlag) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), ymd.yyyymmdd)
+	case 's', 'v', 'q':
+		str := ymd.AsYMDString()
+		if f.Flag('+') && !ymd.IsZero() {
+			str = ymd.AsISOString()
+		}
+		if verb == 'v' {
+			verb = 's'
+		}
+		fmt.Fprintf(f, strings.Replace(fmt.FormatString(f, verb), "+", "", 1), str)
+	default:
+		fmt.Fprintf(f, "%%!%c(ymdflag.YMDFlag=%s)", verb, ymd.AsYMDString())
+	}
+}
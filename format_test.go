@@ -0,0 +1,125 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsSQLDateLiteral(t *testing.T) {
+	assert.Equal(t, "DATE '2023-07-04'", mustYMD(t, 20230704).AsSQLDateLiteral())
+	assert.Equal(t, "DATE '0099-01-02'", mustYMD(t, 990102).AsSQLDateLiteral(), "year is zero-padded")
+	assert.Equal(t, "NULL", YMDFlag{}.AsSQLDateLiteral())
+}
+
+func TestAsISOString(t *testing.T) {
+	assert.Equal(t, "2023-07-04", mustYMD(t, 20230704).AsISOString())
+	assert.Equal(t, "2024-02-29", mustYMD(t, 20240229).AsISOString())
+	assert.Equal(t, "0099-01-02", mustYMD(t, 990102).AsISOString(), "year is zero-padded")
+
+	setNow(t, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, "2023-07-04", zero.AsISOString(), "nil is today")
+	assert.True(t, zero.IsZero(), "receiver is not mutated")
+}
+
+func TestDayOrdinal(t *testing.T) {
+	expected := map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th",
+		11: "11th", 12: "12th", 13: "13th",
+		21: "21st", 22: "22nd", 23: "23rd", 30: "30th", 31: "31st",
+	}
+	for day, ordinal := range expected {
+		assert.Equal(t, ordinal, mustYMD(t, 20230700+day).DayOrdinal(), "day %d", day)
+	}
+
+	setNow(t, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "4th", mustYMDIn(t, 0, time.UTC).DayOrdinal(), "nil is today")
+}
+
+func TestHumanizeSince(t *testing.T) {
+	// 2023-07-04 22:00 UTC is already Jul 5 in Tokyo
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	expected := map[int]string{
+		20230704: "today",
+		20230703: "yesterday",
+		20230705: "tomorrow",
+		20230701: "3 days ago",
+		20230709: "in 5 days",
+		20220704: "365 days ago",
+	}
+	for yyyymmdd, phrase := range expected {
+		assert.Equal(t, phrase, mustYMDIn(t, yyyymmdd, time.UTC).HumanizeSince(), "%d", yyyymmdd)
+	}
+
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	assert.Equal(t, "yesterday", mustYMDIn(t, 20230704, tokyo).HumanizeSince(), "today is in the flag's location")
+	assert.Equal(t, "today", mustYMDIn(t, 0, tokyo).HumanizeSince(), "nil is today")
+}
+
+func TestAsFormat(t *testing.T) {
+	ymdFlag := mustYMD(t, 20230704)
+	assert.Equal(t, "2023-07-04", ymdFlag.AsFormat(time.DateOnly))
+	assert.Equal(t, "Jul 4, 2023", ymdFlag.AsFormat("Jan 2, 2006"))
+	assert.Equal(t, "Tuesday 04/07/23", ymdFlag.AsFormat("Monday 02/01/06"))
+	assert.Equal(t, "00:00:00", ymdFlag.AsFormat(time.TimeOnly), "time of day is midnight")
+	assert.Equal(t, "2023-07-04T00:00:00+14:00", mustYMDIn(t, 20230704, time.FixedZone("UTC+14", 14*60*60)).AsFormat(time.RFC3339))
+
+	setNow(t, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2023-07-04", mustYMDIn(t, 0, time.UTC).AsFormat(time.DateOnly), "nil is today")
+}
+
+func TestAsDirPathDepth(t *testing.T) {
+	ymdFlag := mustYMD(t, 20230704)
+	assert.Equal(t, "2023", ymdFlag.AsDirPathDepth(1, '/'))
+	assert.Equal(t, "2023/07", ymdFlag.AsDirPathDepth(2, '/'))
+	assert.Equal(t, "2023/07/04", ymdFlag.AsDirPathDepth(3, '/'))
+	assert.Equal(t, FormatDirPath(ymdFlag, '/'), ymdFlag.AsDirPathDepth(3, '/'), "depth 3 is FormatDirPath")
+	assert.Equal(t, `2023\07`, ymdFlag.AsDirPathDepth(2, '\\'))
+	assert.Equal(t, "0099-01", mustYMD(t, 990102).AsDirPathDepth(2, '-'), "zero-padded")
+
+	assert.Equal(t, "", ymdFlag.AsDirPathDepth(0, '/'), "clamped to nothing")
+	assert.Equal(t, "", ymdFlag.AsDirPathDepth(-1, '/'), "clamped to nothing")
+	assert.Equal(t, "2023/07/04", ymdFlag.AsDirPathDepth(4, '/'), "clamped to 3 levels")
+	assert.Equal(t, "", YMDFlag{}.AsDirPathDepth(2, '/'))
+}
+
+func TestAsPartitionPath(t *testing.T) {
+	assert.Equal(t, "year=2023/month=07/day=04", mustYMD(t, 20230704).AsPartitionPath())
+	assert.Equal(t, "year=2023/month=12/day=31", mustYMD(t, 20231231).AsPartitionPath())
+	assert.Equal(t, `year=2023\month=07\day=04`, mustYMD(t, 20230704).AsPartitionPathSep('\\'))
+	assert.Equal(t, "year=0099:month=01:day=02", mustYMD(t, 990102).AsPartitionPathSep(':'))
+	assert.Equal(t, "", YMDFlag{}.AsPartitionPath())
+	assert.Equal(t, "", YMDFlag{}.AsPartitionPathSep('\\'))
+}
+
+func TestFormat(t *testing.T) {
+	ymdFlag := mustYMD(t, 20230704)
+	assert.Equal(t, "20230704", fmt.Sprintf("%d", ymdFlag))
+	assert.Equal(t, "0020230704", fmt.Sprintf("%010d", ymdFlag), "int flags apply")
+	assert.Equal(t, "20230704", fmt.Sprintf("%s", ymdFlag))
+	assert.Equal(t, "20230704", fmt.Sprintf("%v", ymdFlag))
+	assert.Equal(t, "20230704", fmt.Sprint(ymdFlag))
+	assert.Equal(t, "2023-07-04", fmt.Sprintf("%+s", ymdFlag))
+	assert.Equal(t, "2023-07-04", fmt.Sprintf("%+v", ymdFlag))
+	assert.Equal(t, `"20230704"`, fmt.Sprintf("%q", ymdFlag))
+	assert.Equal(t, `"2023-07-04"`, fmt.Sprintf("%+q", ymdFlag))
+	assert.Equal(t, "[  2023-07-04]", fmt.Sprintf("[%+12s]", ymdFlag), "string flags apply")
+	assert.Equal(t, "[20230704  ]", fmt.Sprintf("[%-10s]", ymdFlag), "string flags apply")
+	assert.Equal(t, "%!x(ymdflag.YMDFlag=20230704)", fmt.Sprintf("%x", ymdFlag))
+	assert.Equal(t, "on 2023-07-04 and 20240229", fmt.Sprintf("on %+s and %d", ymdFlag, mustYMD(t, 20240229)))
+
+	var zero YMDFlag
+	assert.Equal(t, "0", fmt.Sprintf("%d", zero))
+	assert.Equal(t, "", fmt.Sprintf("%s", zero))
+	assert.Equal(t, "", fmt.Sprintf("%+s", zero))
+	assert.Equal(t, `""`, fmt.Sprintf("%q", zero))
+	assert.True(t, zero.IsZero(), "not resolved")
+
+	ptr := &ymdFlag
+	assert.Equal(t, "2023-07-04", fmt.Sprintf("%+s", ptr), "pointers format the same")
+}
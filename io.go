@@ -0,0 +1,74 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ReadYMDs reads one date per line from `r`, parsing each as Parse does, with the given location,
+// except that relative dates such as `yesterday` are never accepted.
+// Surrounding whitespace is trimmed and blank lines are skipped.
+// Returns an error, with its line number, for the first invalid line.  A `0` line is invalid, wrapping ErrBadFormat,
+// rather than a nil YMDFlag which would resolve to today.
+func ReadYMDs(r io.Reader, loc *time.Location) ([]YMDFlag, error) {
+	var result []YMDFlag
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		ymd, err := parse(line, loc, false)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if ymd.IsZero() {
+			return nil, fmt.Errorf("line %d: expect a date rather than 0: %w", lineNum, ErrBadFormat)
+		}
+		result = append(result, ymd)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %w", err)
+	}
+	return result, nil
+}
+
+// WriteYMDs writes each of the `flags` to `w`, one per line, formatted with the Go time `layout`.
+// An empty `layout` writes the 8-digit `YYYYMMDD` form, which ReadYMDs reads back.
+// Nil YMDFlags are resolved to today in their location, without mutating the slice.
+func WriteYMDs(w io.Writer, flags []YMDFlag, layout string) error {
+	bw := bufio.NewWriter(w)
+	for _, ymd := range flags {
+		var str string
+		if layout == "" {
+			str = ymd.resolved().AsYMDString()
+		} else {
+			str = ymd.AsTime().Format(layout)
+		}
+		if _, err := bw.WriteString(str + "\n"); err != nil {
+			return fmt.Errorf("failed to write %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write %w", err)
+	}
+	return nil
+}
+
+// ParseCSVDate returns the YMDFlag in the given location for a CSV cell, parsed as Parse does,
+// such as `YYYYMMDD`, `YYYY-MM-DD`, or `YYYY/MM/DD`.
+// Surrounding whitespace and double quotes, as left by naive CSV splitting, are trimmed.
+// An empty cell results in a nil YMDFlag.  Unlike Parse, relative dates are never accepted.
+// Use AsYMDString or AsISOString to format the YMDFlag back into a cell.
+func ParseCSVDate(cell string, loc *time.Location) (YMDFlag, error) {
+	cell = strings.TrimSpace(cell)
+	if len(cell) >= 2 && cell[0] == '"' && cell[len(cell)-1] == '"' {
+		cell = strings.TrimSpace(cell[1 : len(cell)-1])
+	}
+	return parse(cell, loc, false)
+}
@@ -0,0 +1,87 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadYMDs(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	input := "20230101\n\n  20230704  \r\n20240229\n"
+	flags, err := ReadYMDs(strings.NewReader(input), loc)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230101, 20230704, 20240229}, ymdInts(flags))
+	for _, flag := range flags {
+		assert.Equal(t, loc, flag.Location())
+	}
+
+	flags, err = ReadYMDs(strings.NewReader(""), loc)
+	assert.NoError(t, err, "empty input is ok")
+	assert.Empty(t, flags)
+
+	_, err = ReadYMDs(strings.NewReader("20230101\n20230229\n"), loc)
+	assert.ErrorContains(t, err, "line 2")
+
+	for _, line := range []string{"0", "today", "yesterday", "+7", "-1"} {
+		_, err = ReadYMDs(strings.NewReader("20230101\n"+line+"\n"), loc)
+		assert.ErrorIs(t, err, ErrBadFormat, line)
+		assert.ErrorContains(t, err, "line 2", line)
+	}
+}
+
+func TestWriteYMDs(t *testing.T) {
+	flags := []YMDFlag{mustYMD(t, 20230101), mustYMD(t, 20230704), mustYMD(t, 20240229)}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteYMDs(&buf, flags, ""))
+	assert.Equal(t, "20230101\n20230704\n20240229\n", buf.String())
+
+	roundTrip, err := ReadYMDs(&buf, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, flags, roundTrip)
+
+	buf.Reset()
+	assert.NoError(t, WriteYMDs(&buf, flags[:2], "2006-01-02"))
+	assert.Equal(t, "2023-01-01\n2023-07-04\n", buf.String())
+
+	var zero YMDFlag
+	buf.Reset()
+	assert.NoError(t, WriteYMDs(&buf, []YMDFlag{zero}, ""))
+	assert.Equal(t, time.Now().Format("20060102")+"\n", buf.String(), "nil flag writes today")
+}
+
+func TestParseCSVDate(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	cells := map[string]int{
+		"20230704":         20230704,
+		"2023-07-04":       20230704,
+		"2023/07/04":       20230704,
+		"  2023-07-04 ":    20230704,
+		`"20230704"`:       20230704,
+		` " 2024/02/29 " `: 20240229,
+		"":                 0,
+		"   ":              0,
+		`""`:               0,
+	}
+	for cell, expected := range cells {
+		ymd, err := ParseCSVDate(cell, loc)
+		assert.NoError(t, err, "%q", cell)
+		assert.Equal(t, expected, ymd.GetYMD(), "%q", cell)
+		assert.Equal(t, loc, ymd.Location(), "%q", cell)
+	}
+
+	for _, cell := range []string{"today", "+1", "2023-07/04", "2023-02-30", `"20230704`, "July 4"} {
+		_, err := ParseCSVDate(cell, loc)
+		assert.Error(t, err, "%q", cell)
+	}
+
+	ymd, _ := ParseCSVDate(" 2023/07/04", loc)
+	assert.Equal(t, "20230704", ymd.AsYMDString())
+	assert.Equal(t, "2023-07-04", ymd.AsISOString())
+}
@@ -0,0 +1,101 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewYMDFlagWithLocationName creates a new nil YMDFlag in the location loaded by `time.LoadLocation(name)`,
+// such as "America/New_York", "UTC", or "Local".  Like LoadLocation, an empty name is UTC.
+// Returns the LoadLocation error if the name is unknown.
+func NewYMDFlagWithLocationName(name string) (YMDFlag, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return YMDFlag{}, err
+	}
+	return YMDFlag{loc: loc}, nil
+}
+
+// NewYMDFlagWithLocationEnv creates a new nil YMDFlag in the location named by the environment variable `key`,
+// interpreted like the `TZ` environment variable: if it is unset the location is nil, meaning local time,
+// if it is empty the location is UTC, and a leading ':' is ignored.
+// Returns the LoadLocation error if the name is unknown.
+func NewYMDFlagWithLocationEnv(key string) (YMDFlag, error) {
+	name, ok := os.LookupEnv(key)
+	if !ok {
+		return YMDFlag{}, nil
+	}
+	return NewYMDFlagWithLocationName(strings.TrimPrefix(name, ":"))
+}
+
+// ErrMidnightTransition is wrapped by errors for dates whose midnight is skipped or repeated by a DST transition.
+var ErrMidnightTransition = errors.New("midnight transition")
+
+// IsDST returns true if midnight on the YMDFlag's date is in daylight saving time in its location.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) IsDST() bool {
+	return ymd.AsTime().IsDST()
+}
+
+// LocationInfo describes the YMDFlag's location at midnight on its date, to help diagnose timezone discrepancies.
+// It returns the zone abbreviation in effect (such as "EDT"), its offset east of UTC in seconds,
+// and whether daylight saving time is in effect.  The location's own name is available via `Location().String()`.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) LocationInfo() (name string, offsetSeconds int, isDST bool) {
+	t := ymd.AsTime()
+	name, offsetSeconds = t.Zone()
+	return name, offsetSeconds, t.IsDST()
+}
+
+// MidnightIsAmbiguous returns true if midnight on the YMDFlag's date occurs twice in its location,
+// which happens when clocks fall back across midnight, as in America/Havana.
+// In that case, `AsTime` returns one of the two instants.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) MidnightIsAmbiguous() bool {
+	return len(ymd.resolved().midnights()) > 1
+}
+
+// AsTimeSafe returns the YMDFlag as a `time.Time` in its location, as AsTime does, but returns a non-nil error
+// wrapping ErrMidnightTransition if midnight on the date does not occur exactly once in that location.
+// If a DST transition skips midnight, as in America/Havana each March, the time returned is the shifted one
+// from AsTime, whose wall clock is not midnight and may be 23:00 of the previous day.
+// If a transition repeats midnight, it is one of the two instants.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsTimeSafe() (time.Time, error) {
+	ymd = ymd.resolved()
+	t := ymd.AsTime()
+	switch midnights := ymd.midnights(); len(midnights) {
+	case 0:
+		return t, fmt.Errorf("midnight of %s does not exist in %s, shifted to %s: %w",
+			ymd.AsISOString(), t.Location(), t.Format("15:04 MST"), ErrMidnightTransition)
+	case 1:
+		return t, nil
+	default:
+		return t, fmt.Errorf("midnight of %s occurs twice in %s: %w", ymd.AsISOString(), t.Location(), ErrMidnightTransition)
+	}
+}
+
+// midnights returns each distinct instant at which the wall clock reads midnight on the YMDFlag's date
+// in its location.  There are none if a DST transition skips midnight, and two if one repeats it.
+func (ymd YMDFlag) midnights() []time.Time {
+	t := ymd.AsTimeRawWithLoc(nil)
+	wall := YMDToTime(ymd.yyyymmdd, time.UTC)
+	var result []time.Time
+	// the offsets in effect half a day either side cover any transition near midnight
+	for _, probe := range []time.Time{t.Add(-12 * time.Hour), t.Add(12 * time.Hour)} {
+		_, offset := probe.Zone()
+		instant := wall.Add(-time.Duration(offset) * time.Second).In(t.Location())
+		if TimeToYMD(instant) != ymd.yyyymmdd || instant.Hour() != 0 || instant.Minute() != 0 {
+			continue
+		}
+		if len(result) == 0 || !result[0].Equal(instant) {
+			result = append(result, instant)
+		}
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // ensure named locations load everywhere the tests run
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func mustYMDIn(t *testing.T, yyyymmdd int, loc *time.Location) YMDFlag {
+	t.Helper()
+	ymd := mustYMD(t, yyyymmdd)
+	ymd.SetLocation(loc)
+	return ymd
+}
+
+func TestIsDST(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	assert.True(t, mustYMDIn(t, 20230704, newYork).IsDST(), "July is daylight time")
+	assert.False(t, mustYMDIn(t, 20230115, newYork).IsDST(), "January is standard time")
+	assert.False(t, mustYMDIn(t, 20230704, time.UTC).IsDST(), "UTC has no daylight time")
+}
+
+func TestLocationInfo(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	name, offset, isDST := mustYMDIn(t, 20230704, newYork).LocationInfo()
+	assert.Equal(t, "EDT", name)
+	assert.Equal(t, -4*60*60, offset)
+	assert.True(t, isDST)
+
+	name, offset, isDST = mustYMDIn(t, 20230115, newYork).LocationInfo()
+	assert.Equal(t, "EST", name)
+	assert.Equal(t, -5*60*60, offset)
+	assert.False(t, isDST)
+
+	name, offset, isDST = mustYMDIn(t, 20230704, time.UTC).LocationInfo()
+	assert.Equal(t, "UTC", name)
+	assert.Equal(t, 0, offset)
+	assert.False(t, isDST)
+}
+
+func TestMidnightIsAmbiguous(t *testing.T) {
+	// Cuba falls back from 01:00 CDT to 00:00 CST, so midnight occurs twice on Sun Nov 5 2023
+	havana := mustLoadLocation(t, "America/Havana")
+	assert.True(t, mustYMDIn(t, 20231105, havana).MidnightIsAmbiguous(), "fall-back at midnight")
+	assert.False(t, mustYMDIn(t, 20231104, havana).MidnightIsAmbiguous(), "day before")
+	assert.False(t, mustYMDIn(t, 20231106, havana).MidnightIsAmbiguous(), "day after")
+	assert.False(t, mustYMDIn(t, 20230312, havana).MidnightIsAmbiguous(), "skipped midnight is not ambiguous")
+
+	// New York transitions at 02:00, so midnight is never ambiguous
+	newYork := mustLoadLocation(t, "America/New_York")
+	assert.False(t, mustYMDIn(t, 20231105, newYork).MidnightIsAmbiguous())
+	assert.False(t, mustYMDIn(t, 20231105, time.UTC).MidnightIsAmbiguous())
+}
+
+func TestAsTimeSafe(t *testing.T) {
+	// Cuba springs forward from 00:00 CST to 01:00 CDT, so midnight does not exist on Sun Mar 12 2023
+	havana := mustLoadLocation(t, "America/Havana")
+	tm, err := mustYMDIn(t, 20230312, havana).AsTimeSafe()
+	assert.ErrorIs(t, err, ErrMidnightTransition, "skipped midnight")
+	assert.Equal(t, time.Date(2023, time.March, 11, 23, 0, 0, 0, havana), tm, "time.Date shifts back across the gap")
+
+	_, err = mustYMDIn(t, 20231105, havana).AsTimeSafe()
+	assert.ErrorIs(t, err, ErrMidnightTransition, "repeated midnight")
+
+	tm, err = mustYMDIn(t, 20230313, havana).AsTimeSafe()
+	assert.NoError(t, err, "day after")
+	assert.Equal(t, time.Date(2023, time.March, 13, 0, 0, 0, 0, havana), tm)
+
+	newYork := mustLoadLocation(t, "America/New_York")
+	ymdFlag := mustYMDIn(t, 20230312, newYork)
+	tm, err = ymdFlag.AsTimeSafe()
+	assert.NoError(t, err, "New York transitions at 02:00")
+	assert.Equal(t, ymdFlag.AsTime(), tm)
+}
+
+func TestNewYMDFlagWithLocationName(t *testing.T) {
+	ymdFlag, err := NewYMDFlagWithLocationName("America/New_York")
+	assert.NoError(t, err)
+	assert.True(t, ymdFlag.IsZero())
+	assert.Equal(t, "America/New_York", ymdFlag.Location().String())
+
+	ymdFlag, err = NewYMDFlagWithLocationName("UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, ymdFlag.Location())
+
+	ymdFlag, err = NewYMDFlagWithLocationName("Local")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, ymdFlag.Location())
+
+	_, expected := time.LoadLocation("Not/AZone")
+	_, err = NewYMDFlagWithLocationName("Not/AZone")
+	assert.Equal(t, expected, err, "LoadLocation error")
+}
+
+func TestNewYMDFlagWithLocationEnv(t *testing.T) {
+	t.Setenv("YMDFLAG_TEST_TZ", "Asia/Tokyo")
+	ymdFlag, err := NewYMDFlagWithLocationEnv("YMDFLAG_TEST_TZ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", ymdFlag.Location().String())
+
+	t.Setenv("YMDFLAG_TEST_TZ", ":Europe/Paris")
+	ymdFlag, err = NewYMDFlagWithLocationEnv("YMDFLAG_TEST_TZ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", ymdFlag.Location().String(), "leading colon is ignored")
+
+	t.Setenv("YMDFLAG_TEST_TZ", "")
+	ymdFlag, err = NewYMDFlagWithLocationEnv("YMDFLAG_TEST_TZ")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, ymdFlag.Location(), "empty is UTC")
+
+	t.Setenv("YMDFLAG_TEST_TZ", "Not/AZone")
+	_, err = NewYMDFlagWithLocationEnv("YMDFLAG_TEST_TZ")
+	assert.Error(t, err)
+
+	ymdFlag, err = NewYMDFlagWithLocationEnv("YMDFLAG_TEST_TZ_UNSET")
+	assert.NoError(t, err)
+	assert.Nil(t, ymdFlag.Location(), "unset is local time")
+}
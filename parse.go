@@ -0,0 +1,171 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AllowRelativeDates enables Parse and Set to accept dates relative to today,
+// resolved using NowFunc in the YMDFlag's location.
+// These are the case-insensitive keywords "today", "yesterday", and "tomorrow",
+// and signed day offsets such as "-1" for yesterday or "+7" for a week from today.
+// Set it to false to only accept absolute dates such as `YYYYMMDD`.
+// Decoders of stored data, such as UnmarshalJSON, UnmarshalText, and ReadYMDs, never accept relative dates,
+// so that a document decodes to the same dates whenever it is read.
+var AllowRelativeDates = true
+
+// relativeKeywords maps the keywords accepted by Set to their offset in days from today.
+var relativeKeywords = map[string]int{
+	"today":     0,
+	"yesterday": -1,
+	"tomorrow":  1,
+}
+
+// parseRelative returns the offset in days from today of a relative date string, or false if it is not one.
+// Offsets must have an explicit sign, so they never collide with the unsigned `YYYYMMDD` form.
+func parseRelative(value string) (int, bool) {
+	if offset, ok := relativeKeywords[strings.ToLower(value)]; ok {
+		return offset, true
+	}
+	if len(value) < 2 || (value[0] != '+' && value[0] != '-') || !isInt(value[1:]) {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return offset, true
+}
+
+// Parse returns the YMDFlag in location `loc` for `s`, auto-detecting its format.
+// It is the parser behind Set.  Surrounding whitespace is trimmed,
+// and the empty string `""` or the integral sentinel `"0"` results in a nil YMDFlag.
+// Otherwise, the formats are tried in this order, and the first one matching the shape of `s` is used:
+//
+//  1. If AllowRelativeDates is true, the case-insensitive keywords "today", "yesterday", and "tomorrow",
+//     and day offsets with an explicit sign such as "-1" and "+7", resolved using NowFunc in `loc`.
+//     A signed value is always an offset, never a date.
+//  2. `YYYYMMDD`, exactly 8 digits.
+//  3. `YYYY-MM-DD`, the ISO 8601 calendar date, or `YYYY/MM/DD`, with the same separator in both places.
+//  4. `YYYY-DDD`, the ISO 8601 ordinal date, as produced by AsOrdinalString.
+//
+// The shapes do not overlap, so an input is never silently read in another format.
+// Returns a non-nil error wrapping ErrBadFormat if `s` matches no format,
+// or wrapping ErrOutOfRange if it matches a format but is not a valid date.
+func Parse(s string, loc *time.Location) (YMDFlag, error) {
+	return parse(s, loc, AllowRelativeDates)
+}
+
+// ParseWithLayout returns the YMDFlag in location `loc` for `s` parsed with the Go reference `layout`,
+// such as `"02/01/2006"` for day-first dates, for inputs which Parse does not recognize.
+// The date is taken as written in `s`, truncating any time of day; if the layout has a zone or offset,
+// it selects the date written, not the date in `loc`.  A nil `loc` implies local time when the layout has no zone.
+// An empty `s` results in a nil YMDFlag.  Returns a non-nil error wrapping ErrBadFormat if `s` does not match the layout,
+// or wrapping ErrOutOfRange if its year is outside MinYear through MaxYear.
+func ParseWithLayout(s, layout string, loc *time.Location) (YMDFlag, error) {
+	if s == "" {
+		return YMDFlag{loc: loc}, nil
+	}
+	parseLoc := loc
+	if parseLoc == nil {
+		parseLoc = time.Local
+	}
+	t, err := time.ParseInLocation(layout, s, parseLoc)
+	if err != nil {
+		return YMDFlag{}, fmt.Errorf("failed to parse with layout %w: %w", err, ErrBadFormat)
+	}
+	if t.Year() < MinYear || t.Year() > MaxYear {
+		return YMDFlag{}, fmt.Errorf("year %d is not supported, years are 0001 through 9999: %w", t.Year(), ErrOutOfRange)
+	}
+	return YMDFlag{yyyymmdd: civilToYMD(t), loc: loc}, nil
+}
+
+// parse is Parse, with relative dates accepted only if `relative` is true.
+func parse(s string, loc *time.Location, relative bool) (YMDFlag, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return YMDFlag{loc: loc}, nil
+	}
+	ymd := YMDFlag{loc: loc}
+	if relative {
+		if offset, ok := parseRelative(s); ok {
+			yyyymmdd := addDaysYMD(ymd.today(), offset)
+			if err := ValidateYMD(yyyymmdd); err != nil {
+				return YMDFlag{}, fmt.Errorf("failed to validate offset %w", err)
+			}
+			ymd.yyyymmdd = yyyymmdd
+			return ymd, nil
+		}
+	}
+	if compact := stripDateSeparators(s); len(compact) == 8 && isInt(compact) {
+		yyyymmdd, err := StringToYMD(compact)
+		if err != nil {
+			return YMDFlag{}, err
+		}
+		ymd.yyyymmdd = yyyymmdd
+		return ymd, nil
+	}
+	if len(s) == 8 && s[4] == '-' && isInt(s[:4]) && isInt(s[5:]) {
+		year, _ := strconv.Atoi(s[:4])
+		day, _ := strconv.Atoi(s[5:])
+		if year < MinYear {
+			return YMDFlag{}, fmt.Errorf("ordinal year 0000 is not supported, years are 0001 through 9999: %w", ErrOutOfRange)
+		}
+		return ParseJulian(1000*year+day, loc)
+	}
+	if relative {
+		return YMDFlag{}, fmt.Errorf("expect string of format YYYYMMDD, YYYY-MM-DD, YYYY/MM/DD, YYYY-DDD, +N or -N days, or one of today, yesterday, tomorrow: %w", ErrBadFormat)
+	}
+	return YMDFlag{}, fmt.Errorf("expect string of format YYYYMMDD, YYYY-MM-DD, YYYY/MM/DD, or YYYY-DDD: %w", ErrBadFormat)
+}
+
+// stripDateSeparators returns `YYYY-MM-DD` or `YYYY/MM/DD` as `YYYYMMDD`.
+// Any other value, including one with mixed separators, is returned unchanged.
+func stripDateSeparators(value string) string {
+	if len(value) != 10 || value[4] != value[7] || (value[4] != '-' && value[4] != '/') {
+		return value
+	}
+	return value[:4] + value[5:7] + value[8:]
+}
+
+// ParseYMDBytes returns the YMDFlag in the given location for exactly 8 ASCII digits `YYYYMMDD`,
+// such as a field of a fixed-width record.  It does not convert `b` to a string, so it does not allocate
+// unless it returns an error.  Unlike StringToYMD, an empty `b` is an error, but `00000000` is a nil YMDFlag.
+func ParseYMDBytes(b []byte, loc *time.Location) (YMDFlag, error) {
+	if len(b) != 8 {
+		return YMDFlag{}, fmt.Errorf("expect 8 bytes of format YYYYMMDD, got %d: %w", len(b), ErrBadFormat)
+	}
+	yyyymmdd := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return YMDFlag{}, fmt.Errorf("expect 8 bytes of format YYYYMMDD: %w", ErrBadFormat)
+		}
+		yyyymmdd = 10*yyyymmdd + int(c-'0')
+	}
+	if err := ValidateYMD(yyyymmdd); err != nil {
+		return YMDFlag{}, fmt.Errorf("failed to validate bytes %w", err)
+	}
+	return YMDFlag{yyyymmdd: yyyymmdd, loc: loc}, nil
+}
+
+// AppendYMD appends the 8 ASCII digits `YYYYMMDD` of the YMDFlag's date to `dst` and returns the extended slice,
+// like `strconv.AppendInt`, zero-padding each field.  It does not allocate unless `dst` must grow,
+// so it suits writing fixed-width records.  A nil YMDFlag appends `00000000`, which ParseYMDBytes reads back as nil;
+// it is not resolved to today.  A negative value or one of more than 8 digits, which no valid date has,
+// is never truncated into wrong digits; `dst` is returned unchanged.
+func (ymd YMDFlag) AppendYMD(dst []byte) []byte {
+	yyyymmdd := ymd.yyyymmdd
+	if yyyymmdd < 0 || yyyymmdd > 99999999 {
+		return dst
+	}
+	var digits [8]byte
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = byte('0' + yyyymmdd%10)
+		yyyymmdd /= 10
+	}
+	return append(dst, digits[:]...)
+}
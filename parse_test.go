@@ -0,0 +1,305 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetKeywords(t *testing.T) {
+	// 2023-07-04 22:00 UTC is already Jul 5 in UTC+3
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+
+	cases := map[string]int{
+		"today":     20230704,
+		"TODAY":     20230704,
+		"Yesterday": 20230703,
+		"tomorrow":  20230705,
+	}
+	for value, expected := range cases {
+		ymdFlag := mustYMDIn(t, 0, time.UTC)
+		assert.NoError(t, ymdFlag.Set(value), value)
+		assert.Equal(t, expected, ymdFlag.GetYMD(), value)
+	}
+
+	ymdFlag := mustYMDIn(t, 0, time.FixedZone("UTC+3", 3*60*60))
+	assert.NoError(t, ymdFlag.Set("today"))
+	assert.Equal(t, 20230705, ymdFlag.GetYMD(), "today is in the flag's location")
+	assert.NoError(t, ymdFlag.Set("yesterday"))
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "yesterday is in the flag's location")
+
+	assert.NoError(t, ymdFlag.Set("20230101"), "numeric form is unchanged")
+	assert.Equal(t, 20230101, ymdFlag.GetYMD())
+
+	err := ymdFlag.Set("someday")
+	assert.ErrorContains(t, err, "expect string of format YYYYMMDD")
+	assert.ErrorContains(t, err, "today", "error mentions the keywords")
+
+	AllowRelativeDates = false
+	t.Cleanup(func() { AllowRelativeDates = true })
+	assert.Error(t, ymdFlag.Set("today"), "keywords can be disabled")
+	assert.NotContains(t, ymdFlag.Set("someday").Error(), "today")
+}
+
+func TestSetOffsets(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	cases := map[string]int{
+		"-1":  20230703,
+		"+0":  20230704,
+		"-0":  20230704,
+		"+1":  20230705,
+		"+30": 20230803,
+		"-4":  20230630,
+		"+7":  20230711,
+	}
+	for value, expected := range cases {
+		ymdFlag := mustYMDIn(t, 0, time.UTC)
+		assert.NoError(t, ymdFlag.Set(value), value)
+		assert.Equal(t, expected, ymdFlag.GetYMD(), value)
+	}
+
+	ymdFlag := mustYMDIn(t, 0, time.UTC)
+	assert.NoError(t, ymdFlag.Set("20230101"), "8-digit strings are absolute")
+	assert.Equal(t, 20230101, ymdFlag.GetYMD())
+	assert.NoError(t, ymdFlag.Set("20240229"), "8-digit strings are absolute")
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+
+	for _, value := range []string{"+", "-", "1", "+1d", "--1", "+-1", "+99999999", "-99999999999999999999"} {
+		assert.Error(t, ymdFlag.Set(value), value)
+	}
+}
+
+func TestSetSeparators(t *testing.T) {
+	cases := map[string]int{
+		"20230704":   20230704,
+		"2023-07-04": 20230704,
+		"2023/07/04": 20230704,
+		"2024-02-29": 20240229,
+		"0099/01/02": 990102,
+	}
+	for value, expected := range cases {
+		var ymdFlag YMDFlag
+		assert.NoError(t, ymdFlag.Set(value), value)
+		assert.Equal(t, expected, ymdFlag.GetYMD(), value)
+		assert.Equal(t, fmt.Sprintf("%08d", expected), ymdFlag.String(), "canonical form is YYYYMMDD")
+	}
+
+	for _, value := range []string{"2023-07/04", "2023/07-04", "2023.07.04", "2023-7-4", "2023--0704", "20230-7-04", "2023-0a-04"} {
+		var ymdFlag YMDFlag
+		assert.ErrorContains(t, ymdFlag.Set(value), "YYYY-MM-DD", value)
+	}
+
+	var ymdFlag YMDFlag
+	assert.ErrorContains(t, ymdFlag.Set("2023-02-30"), "unnormalized", "invalid dates fail validation")
+	assert.ErrorContains(t, ymdFlag.Set("2023/13/01"), "unnormalized")
+
+	AllowRelativeDates = false
+	t.Cleanup(func() { AllowRelativeDates = true })
+	assert.NoError(t, ymdFlag.Set("2023-07-04"), "separators do not depend on relative dates")
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	assert.EqualError(t, ymdFlag.Set("July 4"), "expect string of format YYYYMMDD, YYYY-MM-DD, YYYY/MM/DD, or YYYY-DDD: bad format")
+}
+
+func TestParse(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo") // already Jul 5
+
+	cases := []struct {
+		input    string
+		loc      *time.Location
+		expected int
+	}{
+		{"", time.UTC, 0},
+		{"0", time.UTC, 0},
+		{" \t", time.UTC, 0},
+		{"today", time.UTC, 20230704},
+		{"Yesterday", time.UTC, 20230703},
+		{"TOMORROW", tokyo, 20230706},
+		{"+7", time.UTC, 20230711},
+		{"-1", tokyo, 20230704},
+		{"+0", time.UTC, 20230704},
+		{"20230704", tokyo, 20230704},
+		{" 20230704\n", time.UTC, 20230704},
+		{"00010101", time.UTC, 10101},
+		{"2023-07-04", time.UTC, 20230704},
+		{"2024/02/29", time.UTC, 20240229},
+		{"2023-185", time.UTC, 20230704},
+		{"2024-366", time.UTC, 20241231},
+		{"2023-001", time.UTC, 20230101},
+	}
+	for _, c := range cases {
+		ymdFlag, err := Parse(c.input, c.loc)
+		assert.NoError(t, err, "%q", c.input)
+		assert.Equal(t, c.expected, ymdFlag.GetYMD(), "%q", c.input)
+		assert.Equal(t, c.loc, ymdFlag.Location(), "%q", c.input)
+	}
+
+	badFormat := []string{"July 4", "2023-7-4", "2023-07/04", "2023.07.04", "202307041", "2023070", "2023-18", "2023-1850", "00", "7d", "+", "2023-W27"}
+	for _, input := range badFormat {
+		_, err := Parse(input, time.UTC)
+		assert.ErrorIs(t, err, ErrBadFormat, "%q", input)
+	}
+	outOfRange := []string{"20230230", "2023-02-30", "2023/13/01", "00001231", "2023-366", "2023-000", "0000-001", "+99999999"}
+	for _, input := range outOfRange {
+		_, err := Parse(input, time.UTC)
+		assert.ErrorIs(t, err, ErrOutOfRange, "%q", input)
+	}
+
+	AllowRelativeDates = false
+	t.Cleanup(func() { AllowRelativeDates = true })
+	for _, input := range []string{"today", "+7", "-1"} {
+		_, err := Parse(input, time.UTC)
+		assert.ErrorIs(t, err, ErrBadFormat, "%q without relative dates", input)
+	}
+	ymdFlag, err := Parse("2023-185", time.UTC)
+	assert.NoError(t, err, "absolute formats do not depend on relative dates")
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+}
+
+func TestParseWithLayout(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/London")
+	ymdFlag, err := ParseWithLayout("04/07/2023", "02/01/2006", loc)
+	assert.NoError(t, err, "day-first")
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location())
+
+	cases := map[string]int{
+		"04/07/23": 20230704,
+		"31/12/68": 20681231,
+		"01/01/69": 19690101,
+		"29/02/00": 20000229,
+	}
+	for s, expected := range cases {
+		ymdFlag, err = ParseWithLayout(s, "02/01/06", loc)
+		assert.NoError(t, err, "two-digit year %q", s)
+		assert.Equal(t, expected, ymdFlag.GetYMD(), "two-digit year %q", s)
+	}
+
+	ymdFlag, err = ParseWithLayout("Jul 4 2023 11:30PM", "Jan 2 2006 3:04PM", nil)
+	assert.NoError(t, err, "time of day is truncated")
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	ymdFlag, err = ParseWithLayout("2023-07-04T23:30:00-05:00", time.RFC3339, mustLoadLocation(t, "Asia/Tokyo"))
+	assert.NoError(t, err)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "the date as written, not in loc")
+
+	ymdFlag, err = ParseWithLayout("", "02/01/2006", loc)
+	assert.NoError(t, err)
+	assert.True(t, ymdFlag.IsZero())
+	assert.Equal(t, loc, ymdFlag.Location())
+
+	for _, s := range []string{"07/04/2023 extra", "2023-07-04", "30/02/2023", "4/7/2023"} {
+		_, err = ParseWithLayout(s, "02/01/2006", loc)
+		assert.ErrorIs(t, err, ErrBadFormat, s)
+	}
+	_, err = ParseWithLayout("0000-01-01", "2006-01-02", loc)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestParseYMDBytes(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	ymdFlag, err := ParseYMDBytes([]byte("20230704"), loc)
+	assert.NoError(t, err)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location())
+
+	record := []byte("ACME    20240229  100.25")
+	ymdFlag, err = ParseYMDBytes(record[8:16], nil)
+	assert.NoError(t, err, "field of a fixed-width record")
+	assert.Equal(t, 20240229, ymdFlag.GetYMD())
+
+	for _, b := range []string{"", "2023074", "202307045", "2023-7-4", "2023070a", " 2023070", "+2023070"} {
+		_, err := ParseYMDBytes([]byte(b), loc)
+		assert.True(t, errors.Is(err, ErrBadFormat), "%q: %v", b, err)
+	}
+	ymdFlag, err = ParseYMDBytes([]byte("00000000"), loc)
+	assert.NoError(t, err, "zeros are nil, as in StringToYMD")
+	assert.True(t, ymdFlag.IsZero())
+	for _, b := range []string{"20230230", "20231301", "20230700"} {
+		_, err := ParseYMDBytes([]byte(b), loc)
+		assert.True(t, errors.Is(err, ErrOutOfRange), "%q: %v", b, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = ParseYMDBytes(record[8:16], loc)
+	})
+	assert.Zero(t, allocs, "does not allocate")
+}
+
+func TestAppendYMD(t *testing.T) {
+	cases := map[int]string{
+		20230704: "20230704",
+		20231231: "20231231",
+		20240101: "20240101",
+		990102:   "00990102",
+		10101:    "00010101",
+		0:        "00000000",
+	}
+	for yyyymmdd, expected := range cases {
+		assert.Equal(t, expected, string(mustYMD(t, yyyymmdd).AppendYMD(nil)), "%d", yyyymmdd)
+	}
+
+	record := []byte("ID42|")
+	record = mustYMD(t, 20230704).AppendYMD(record)
+	record = append(record, '|')
+	record = mustYMD(t, 20240229).AppendYMD(record)
+	assert.Equal(t, "ID42|20230704|20240229", string(record))
+
+	for _, yyyymmdd := range []int{20230704, 10101, 0} {
+		ymdFlag, err := ParseYMDBytes(mustYMD(t, yyyymmdd).AppendYMD(nil), time.UTC)
+		assert.NoError(t, err, "%d", yyyymmdd)
+		assert.Equal(t, yyyymmdd, ymdFlag.GetYMD(), "round trip through ParseYMDBytes")
+	}
+
+	dst := make([]byte, 0, 16)
+	ymdFlag := mustYMD(t, 20230704)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = ymdFlag.AppendYMD(dst[:0])
+	})
+	assert.Zero(t, allocs, "does not allocate with capacity")
+}
+
+func BenchmarkAppendYMD(b *testing.B) {
+	ymdFlag := YMDFlag{yyyymmdd: 20230704}
+	dst := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = ymdFlag.AppendYMD(dst[:0])
+	}
+}
+
+// BenchmarkAppendItoa is the allocating path which AsYMDString took before AppendYMD, for comparison.
+func BenchmarkAppendItoa(b *testing.B) {
+	ymdFlag := YMDFlag{yyyymmdd: 20230704}
+	dst := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = append(dst[:0], strconv.Itoa(ymdFlag.yyyymmdd)...)
+	}
+}
+
+func BenchmarkParseYMDBytes(b *testing.B) {
+	record := []byte("20230704")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseYMDBytes(record, time.UTC); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringToYMDFromBytes(b *testing.B) {
+	record := []byte("20230704")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := StringToYMD(string(record)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
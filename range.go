@@ -0,0 +1,370 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// YMDRange represents an inclusive span of dates from Start to End.
+//
+// Endpoints are compared on their calendar dates, ignoring location.
+// Dates produced by iterating a range have the location of Start.
+// A reversed range, with End before Start, iterates backward from Start to End.
+type YMDRange struct {
+	Start YMDFlag // first date of the range, inclusive
+	End   YMDFlag // last date of the range, inclusive
+}
+
+// BoundingRange returns the smallest range containing all of the `flags`, from the earliest to the latest date.
+// Returns false if `flags` is empty.  The endpoints keep the locations of the flags they came from.
+// Nil YMDFlags are resolved to today.
+func BoundingRange(flags []YMDFlag) (YMDRange, bool) {
+	if len(flags) == 0 {
+		return YMDRange{}, false
+	}
+	first := flags[0].resolved()
+	r := YMDRange{Start: first, End: first}
+	for _, ymd := range flags[1:] {
+		ymd = ymd.resolved()
+		if ymd.yyyymmdd < r.Start.yyyymmdd {
+			r.Start = ymd
+		}
+		if ymd.yyyymmdd > r.End.yyyymmdd {
+			r.End = ymd
+		}
+	}
+	return r, true
+}
+
+// Len returns the number of dates in the range, including both endpoints, without iterating it.
+// Nil endpoints are resolved to today.
+func (r YMDRange) Len() int {
+	span := daysSinceEpoch(r.End.resolved().yyyymmdd) - daysSinceEpoch(r.Start.resolved().yyyymmdd)
+	if span < 0 {
+		span = -span
+	}
+	return span + 1
+}
+
+// Days returns each date of the range, from Start to End inclusive.
+func (r YMDRange) Days() []YMDFlag {
+	days := make([]YMDFlag, 0, r.Len())
+	r.each(func(ymd YMDFlag) bool {
+		days = append(days, ymd)
+		return true
+	})
+	return days
+}
+
+// RandomDate returns a uniformly random date within the range, drawn from `rng` so that results are reproducible.
+// The returned YMDFlag has the location of Start.  Nil endpoints are resolved to today.
+func (r YMDRange) RandomDate(rng *rand.Rand) YMDFlag {
+	start, end := r.Start.resolved(), r.End.resolved()
+	offset := rng.Intn(r.Len())
+	if start.yyyymmdd > end.yyyymmdd {
+		offset = -offset
+	}
+	start.yyyymmdd = addDaysYMD(start.yyyymmdd, offset)
+	return start
+}
+
+// Ordered returns the range with Start and End swapped if Start is after End,
+// so that Start is never after End.  Nil endpoints are compared as today.
+func (r YMDRange) Ordered() YMDRange {
+	if r.Start.resolved().yyyymmdd > r.End.resolved().yyyymmdd {
+		r.Start, r.End = r.End, r.Start
+	}
+	return r
+}
+
+// Validate returns nil if both endpoints are valid and Start is not after End.
+// Otherwise, returns an error.  Nil endpoints are resolved to today before comparing.
+func (r YMDRange) Validate() error {
+	if err := ValidateYMD(r.Start.yyyymmdd); err != nil {
+		return fmt.Errorf("invalid range start %w", err)
+	}
+	if err := ValidateYMD(r.End.yyyymmdd); err != nil {
+		return fmt.Errorf("invalid range end %w", err)
+	}
+	if start, end := r.Start.resolved(), r.End.resolved(); start.yyyymmdd > end.yyyymmdd {
+		return fmt.Errorf("range start %08d is after end %08d", start.yyyymmdd, end.yyyymmdd)
+	}
+	return nil
+}
+
+// Contains returns true if the date `d` is within the range, including its endpoints.
+// A reversed range contains the same dates as its Ordered form.  Nil dates are resolved to today.
+func (r YMDRange) Contains(d YMDFlag) bool {
+	start, end, day := r.bounds(d)
+	return start <= day && day <= end
+}
+
+// ContainsExclusive returns true if the date `d` is strictly after the earlier endpoint and strictly before the later one.
+// A reversed range contains the same dates as its Ordered form.  Nil dates are resolved to today.
+func (r YMDRange) ContainsExclusive(d YMDFlag) bool {
+	start, end, day := r.bounds(d)
+	return start < day && day < end
+}
+
+// Between returns true if the YMDFlag's date is within `start` through `end` inclusive,
+// which is `YMDRange{start, end}.Contains(ymd)`.  The endpoints may be given in either order.
+// Nil dates are resolved to today.
+func (ymd YMDFlag) Between(start, end YMDFlag) bool {
+	return YMDRange{Start: start, End: end}.Contains(ymd)
+}
+
+// BetweenHalfOpen returns true if the YMDFlag's date is within the half-open interval from `start` to `end`,
+// including the earlier endpoint but excluding the later one.  The endpoints may be given in either order.
+// Nil dates are resolved to today.
+func (ymd YMDFlag) BetweenHalfOpen(start, end YMDFlag) bool {
+	lower, upper, day := YMDRange{Start: start, End: end}.bounds(ymd)
+	return lower <= day && day < upper
+}
+
+// bounds returns the ordered, resolved integral `yyyymmdd` endpoints of the range and of `d`.
+func (r YMDRange) bounds(d YMDFlag) (start, end, day int) {
+	start, end, day = r.Start.resolved().yyyymmdd, r.End.resolved().yyyymmdd, d.resolved().yyyymmdd
+	if start > end {
+		start, end = end, start
+	}
+	return start, end, day
+}
+
+// Intersect returns the overlap of the two ranges, as an ordered range.
+// Returns false if the ranges are disjoint, in which case the returned range is meaningless.
+// As with Contains, reversed ranges are treated as their Ordered form and nil endpoints are resolved to today,
+// so an open range from YMDRangeFlag's `"YYYYMMDD.."` runs through today.
+// The endpoints of the result are taken, resolved, from whichever range supplied them.
+func (r YMDRange) Intersect(other YMDRange) (YMDRange, bool) {
+	result, second := r.resolved().Ordered(), other.resolved().Ordered()
+	if second.Start.yyyymmdd > result.Start.yyyymmdd {
+		result.Start = second.Start
+	}
+	if second.End.yyyymmdd < result.End.yyyymmdd {
+		result.End = second.End
+	}
+	if result.Start.yyyymmdd > result.End.yyyymmdd {
+		return YMDRange{}, false
+	}
+	return result, true
+}
+
+// resolved returns the range with nil endpoints resolved to today in their own locations.
+func (r YMDRange) resolved() YMDRange {
+	return YMDRange{Start: r.Start.resolved(), End: r.End.resolved()}
+}
+
+// ClampTo returns the range restricted to lie within `bound`, which is their intersection.
+// Returns false if the range lies entirely outside of `bound`.
+// As with Intersect, reversed ranges are ordered and nil endpoints are resolved to today.
+func (r YMDRange) ClampTo(bound YMDRange) (YMDRange, bool) {
+	return r.Intersect(bound)
+}
+
+// IntersectAll returns the intersection of all the ranges, which is the window common to every one of them.
+// Returns false if the slice is empty or if any of the ranges do not overlap.
+// As with Intersect, reversed ranges are ordered and nil endpoints are resolved to today.
+func IntersectAll(ranges []YMDRange) (YMDRange, bool) {
+	if len(ranges) == 0 {
+		return YMDRange{}, false
+	}
+	result := ranges[0].resolved().Ordered()
+	for _, r := range ranges[1:] {
+		var ok bool
+		if result, ok = result.Intersect(r); !ok {
+			return YMDRange{}, false
+		}
+	}
+	return result, true
+}
+
+// DateAtFraction returns the date which is the fraction `f` of the way through the range,
+// computed as Start plus round(f * days) where days is the number of days from Start to End.
+// `f` is clamped to [0,1], so 0 returns Start and 1 returns End, and a NaN `f` also returns Start.
+// The returned YMDFlag has the location of Start.  Nil endpoints are resolved to today.
+func (r YMDRange) DateAtFraction(f float64) YMDFlag {
+	start, end := r.Start.resolved(), r.End.resolved()
+	if math.IsNaN(f) {
+		return start
+	}
+	f = math.Max(0, math.Min(1, f))
+	span := daysSinceEpoch(end.yyyymmdd) - daysSinceEpoch(start.yyyymmdd)
+	start.yyyymmdd = addDaysYMD(start.yyyymmdd, int(math.Round(f*float64(span))))
+	return start
+}
+
+// FindFirst returns the first date in the range, iterating from Start toward End, for which `pred` returns true.
+// Returns false if no date matches.  The returned YMDFlag has the location of Start.
+func (r YMDRange) FindFirst(pred func(YMDFlag) bool) (YMDFlag, bool) {
+	var found YMDFlag
+	var ok bool
+	r.each(func(ymd YMDFlag) bool {
+		if pred(ymd) {
+			found, ok = ymd, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// Count returns the number of dates in the range for which `pred` returns true.
+func (r YMDRange) Count(pred func(YMDFlag) bool) int {
+	count := 0
+	r.each(func(ymd YMDFlag) bool {
+		if pred(ymd) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// DaysOnWeekdays returns the dates of the range falling on any of the given weekdays, in range order.
+func (r YMDRange) DaysOnWeekdays(days ...time.Weekday) []YMDFlag {
+	var wanted [7]bool
+	for _, day := range days {
+		if day >= time.Sunday && day <= time.Saturday {
+			wanted[day] = true
+		}
+	}
+	var result []YMDFlag
+	r.each(func(ymd YMDFlag) bool {
+		if wanted[ymd.Weekday()] {
+			result = append(result, ymd)
+		}
+		return true
+	})
+	return result
+}
+
+// OccurrencesOfDay returns each date within the range falling on day-of-month `dom`, in ascending order.
+// Months lacking that day are skipped, so the 31st occurs only in 31-day months.
+// Returns nil if `dom` is not within 1 through 31.
+// The dates have the location of Start.  Nil endpoints are resolved to today.
+func (r YMDRange) OccurrencesOfDay(dom int) []YMDFlag {
+	if dom < 1 || dom > 31 {
+		return nil
+	}
+	start, end := r.Start.resolved(), r.End.resolved()
+	if start.yyyymmdd > end.yyyymmdd {
+		start.yyyymmdd, end.yyyymmdd = end.yyyymmdd, start.yyyymmdd
+	}
+	var result []YMDFlag
+	year, month, _ := start.AsYearMonthDay()
+	for 10000*year+100*month+1 <= end.yyyymmdd {
+		yyyymmdd := 10000*year + 100*month + dom
+		if yyyymmdd <= monthEndYMD(year, month) && yyyymmdd >= start.yyyymmdd && yyyymmdd <= end.yyyymmdd {
+			ymd := start
+			ymd.yyyymmdd = yyyymmdd
+			result = append(result, ymd)
+		}
+		if month++; month > 12 {
+			year, month = year+1, 1
+		}
+	}
+	return result
+}
+
+// SQLBounds returns the range as half-open time bounds, for predicates like `col >= ? AND col < ?`.
+// `start` is midnight of Start and `endExclusive` is midnight of the day after End, both in the location of Start.
+// Nil endpoints are resolved to today.  A reversed range is not reordered.
+func (r YMDRange) SQLBounds() (start, endExclusive time.Time) {
+	first, last := r.Start.resolved(), r.End.resolved()
+	start = YMDToTime(first.yyyymmdd, first.loc)
+	endExclusive = YMDToTime(addDaysYMD(last.yyyymmdd, 1), first.loc)
+	return start, endExclusive
+}
+
+// MapRange returns the result of calling `fn` with each date of the range, in order.
+// It is a function rather than a YMDRange method because Go methods cannot have type parameters.
+func MapRange[T any](r YMDRange, fn func(YMDFlag) T) []T {
+	var result []T
+	r.each(func(ymd YMDFlag) bool {
+		result = append(result, fn(ymd))
+		return true
+	})
+	return result
+}
+
+// DirPathsInRange returns the FormatDirPath of each day from `start` through `end` inclusive, using `separator`.
+// If `start` is after `end`, the paths are in descending order.
+// Nil dates are resolved to today in their own locations.
+func DirPathsInRange(start, end YMDFlag, separator rune) []string {
+	return MapRange(YMDRange{Start: start, End: end}, func(ymd YMDFlag) string {
+		return FormatDirPath(ymd, separator)
+	})
+}
+
+// RangeStep returns every `stepDays`-th date from `start` toward `end` inclusive: `start`, `start+stepDays`, and so on,
+// stopping at the last date not beyond `end`.  If `start` is after `end`, the dates step backwards in descending order.
+// Returns a non-nil error if `stepDays` is not positive.
+// The dates have the location of `start`.  Nil dates are resolved to today in their own locations.
+func RangeStep(start, end YMDFlag, stepDays int) ([]YMDFlag, error) {
+	if stepDays <= 0 {
+		return nil, fmt.Errorf("step of %d days is not positive", stepDays)
+	}
+	start = start.resolved()
+	span := daysSinceEpoch(end.resolved().yyyymmdd) - daysSinceEpoch(start.yyyymmdd)
+	direction := 1
+	if span < 0 {
+		span, direction = -span, -1
+	}
+	result := make([]YMDFlag, span/stepDays+1)
+	for i := range result {
+		result[i] = start
+		result[i].yyyymmdd = addDaysYMD(start.yyyymmdd, direction*i*stepDays)
+	}
+	return result, nil
+}
+
+// MonthPrefixesInRange returns one `"root/YYYY/MM"` prefix, using `separator`, for each distinct month
+// touched by the inclusive range from `start` to `end`, for listing storage by month rather than by day.
+// Trailing separators on `root` are trimmed, and an empty `root` gives just `"YYYY/MM"`.
+// If `start` is after `end`, the prefixes are in descending order.
+// Nil dates are resolved to today in their own locations.
+func MonthPrefixesInRange(start, end YMDFlag, root string, separator rune) []string {
+	first, last := monthIndex(start.resolved().yyyymmdd), monthIndex(end.resolved().yyyymmdd)
+	step := 1
+	if first > last {
+		step = -1
+	}
+	root = strings.TrimRight(root, string(separator))
+	var result []string
+	for index := first; ; index += step {
+		prefix := fmt.Sprintf("%04d%c%02d", index/12, separator, index%12+1)
+		if root != "" {
+			prefix = root + string(separator) + prefix
+		}
+		result = append(result, prefix)
+		if index == last {
+			return result
+		}
+	}
+}
+
+// monthIndex returns the number of months from year 0 to the month of `yyyymmdd`.
+func monthIndex(yyyymmdd int) int {
+	return 12*(yyyymmdd/10000) + (yyyymmdd/100)%100 - 1
+}
+
+// each calls `fn` with each date of the range, from Start toward End inclusive, until `fn` returns false.
+// The dates have the location of Start.  Nil endpoints are resolved to today.
+func (r YMDRange) each(fn func(YMDFlag) bool) {
+	day, end := r.Start.resolved(), r.End.resolved()
+	step := 1
+	if day.yyyymmdd > end.yyyymmdd {
+		step = -1
+	}
+	for {
+		if !fn(day) || day.yyyymmdd == end.yyyymmdd {
+			return
+		}
+		day.yyyymmdd = addDaysYMD(day.yyyymmdd, step)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build go1.23
+
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"iter"
+)
+
+// Enumerate returns an iterator over the zero-based index and date of each day of the range, in order.
+// The dates have the location of Start.  Nil endpoints are resolved to today when iteration begins.
+// It requires Go 1.23 or later.
+func (r YMDRange) Enumerate() iter.Seq2[int, YMDFlag] {
+	return func(yield func(int, YMDFlag) bool) {
+		index := 0
+		r.each(func(ymd YMDFlag) bool {
+			if !yield(index, ymd) {
+				return false
+			}
+			index++
+			return true
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumerate(t *testing.T) {
+	r := mustRange(t, 20231230, 20240102)
+	var indices, dates []int
+	for i, ymd := range r.Enumerate() {
+		indices = append(indices, i)
+		dates = append(dates, ymd.GetYMD())
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, indices, "indices are contiguous from 0")
+	assert.Equal(t, []int{20231230, 20231231, 20240101, 20240102}, dates)
+
+	dates = nil
+	for i, ymd := range mustRange(t, 20230705, 20230701).Enumerate() {
+		if i == 2 {
+			break
+		}
+		dates = append(dates, ymd.GetYMD())
+	}
+	assert.Equal(t, []int{20230705, 20230704}, dates, "reversed, stopping early")
+}
@@ -0,0 +1,460 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustYMD(t *testing.T, yyyymmdd int) YMDFlag {
+	t.Helper()
+	ymd, err := NewYMDFlagFromInt(yyyymmdd)
+	if err != nil {
+		t.Fatalf("bad test date %d: %v", yyyymmdd, err)
+	}
+	return ymd
+}
+
+func mustRange(t *testing.T, start, end int) YMDRange {
+	t.Helper()
+	return YMDRange{Start: mustYMD(t, start), End: mustYMD(t, end)}
+}
+
+func TestIntersect(t *testing.T) {
+	jan := mustRange(t, 20230101, 20230131)
+	result, ok := jan.Intersect(mustRange(t, 20230115, 20230215))
+	assert.True(t, ok)
+	assert.Equal(t, mustRange(t, 20230115, 20230131), result)
+
+	_, ok = jan.Intersect(mustRange(t, 20230201, 20230228))
+	assert.False(t, ok, "disjoint")
+
+	result, ok = jan.Intersect(mustRange(t, 20230131, 20230101))
+	assert.True(t, ok, "reversed range is treated as ordered")
+	assert.Equal(t, jan, result)
+	result, ok = mustRange(t, 20230120, 20230110).Intersect(jan)
+	assert.True(t, ok, "reversed receiver")
+	assert.Equal(t, mustRange(t, 20230110, 20230120), result)
+
+	setNow(t, time.Date(2023, time.January, 20, 12, 0, 0, 0, time.UTC))
+	openEnd := YMDRange{Start: mustYMD(t, 20230115), End: mustYMDIn(t, 0, time.UTC)}
+	assert.True(t, openEnd.Contains(mustYMD(t, 20230120)))
+	result, ok = jan.Intersect(openEnd)
+	assert.True(t, ok, "nil End is today")
+	assert.Equal(t, []int{20230115, 20230120}, []int{result.Start.GetYMD(), result.End.GetYMD()})
+
+	openStart := YMDRange{Start: mustYMDIn(t, 0, time.UTC), End: mustYMD(t, 20230215)}
+	result, ok = jan.Intersect(openStart)
+	assert.True(t, ok, "nil Start is today")
+	assert.Equal(t, []int{20230120, 20230131}, []int{result.Start.GetYMD(), result.End.GetYMD()})
+
+	_, ok = mustRange(t, 20230201, 20230228).Intersect(openEnd)
+	assert.False(t, ok, "open range ends today")
+}
+
+func TestIntersectAll(t *testing.T) {
+	ranges := []YMDRange{
+		mustRange(t, 20230101, 20230131),
+		mustRange(t, 20230110, 20230220),
+		mustRange(t, 20221201, 20230115),
+	}
+	result, ok := IntersectAll(ranges)
+	assert.True(t, ok, "ranges share a common window")
+	assert.Equal(t, 20230110, result.Start.GetYMD())
+	assert.Equal(t, 20230115, result.End.GetYMD())
+
+	ranges = []YMDRange{
+		mustRange(t, 20230101, 20230131),
+		mustRange(t, 20230110, 20230220),
+		mustRange(t, 20230201, 20230210),
+	}
+	_, ok = IntersectAll(ranges)
+	assert.False(t, ok, "first and last ranges are disjoint")
+
+	_, ok = IntersectAll(nil)
+	assert.False(t, ok, "empty slice has no intersection")
+
+	result, ok = IntersectAll([]YMDRange{mustRange(t, 20230101, 20230101)})
+	assert.True(t, ok, "single day range intersects itself")
+	assert.Equal(t, 20230101, result.Start.GetYMD())
+	assert.Equal(t, 20230101, result.End.GetYMD())
+
+	ranges = []YMDRange{
+		mustRange(t, 20230131, 20230101),
+		mustRange(t, 20230220, 20230110),
+	}
+	result, ok = IntersectAll(ranges)
+	assert.True(t, ok, "reversed ranges are ordered")
+	assert.Equal(t, mustRange(t, 20230110, 20230131), result)
+
+	setNow(t, time.Date(2023, time.January, 20, 12, 0, 0, 0, time.UTC))
+	ranges = []YMDRange{
+		mustRange(t, 20230101, 20230131),
+		{Start: mustYMD(t, 20230105), End: mustYMDIn(t, 0, time.UTC)},
+		{Start: mustYMDIn(t, 0, time.UTC), End: mustYMD(t, 20231231)},
+	}
+	result, ok = IntersectAll(ranges)
+	assert.True(t, ok, "nil endpoints are today")
+	assert.Equal(t, []int{20230120, 20230120}, []int{result.Start.GetYMD(), result.End.GetYMD()})
+
+	// a single range is ordered and resolved, as with several
+	result, ok = IntersectAll([]YMDRange{mustRange(t, 20230131, 20230101)})
+	assert.True(t, ok, "single reversed range")
+	assert.Equal(t, mustRange(t, 20230101, 20230131), result)
+	result, ok = IntersectAll([]YMDRange{{Start: mustYMD(t, 20230105), End: mustYMDIn(t, 0, time.UTC)}})
+	assert.True(t, ok, "single range with a nil endpoint")
+	assert.Equal(t, YMDRange{Start: mustYMD(t, 20230105), End: mustYMDIn(t, 20230120, time.UTC)}, result, "nil End is resolved")
+}
+
+func TestDateAtFraction(t *testing.T) {
+	r := mustRange(t, 20230101, 20230111)
+	assert.Equal(t, 20230101, r.DateAtFraction(0).GetYMD(), "zero is Start")
+	assert.Equal(t, 20230111, r.DateAtFraction(1).GetYMD(), "one is End")
+	assert.Equal(t, 20230106, r.DateAtFraction(0.5).GetYMD(), "half way")
+	assert.Equal(t, 20230104, r.DateAtFraction(0.3).GetYMD(), "30 percent")
+	assert.Equal(t, 20230101, r.DateAtFraction(-1).GetYMD(), "clamped below")
+	assert.Equal(t, 20230111, r.DateAtFraction(2).GetYMD(), "clamped above")
+	assert.Equal(t, 20230101, r.DateAtFraction(math.NaN()).GetYMD(), "NaN is Start")
+	assert.Equal(t, 20230111, r.DateAtFraction(math.Inf(1)).GetYMD(), "infinity is clamped")
+	assert.Equal(t, 20230101, r.DateAtFraction(math.Inf(-1)).GetYMD(), "negative infinity is clamped")
+	assert.Equal(t, 20230111, mustRange(t, 20230111, 20230101).DateAtFraction(math.NaN()).GetYMD(), "NaN is Start when reversed")
+
+	r = mustRange(t, 20231225, 20240104)
+	assert.Equal(t, 20231230, r.DateAtFraction(0.5).GetYMD(), "across a year boundary")
+}
+
+func TestRangeValidate(t *testing.T) {
+	assert.NoError(t, mustRange(t, 20230101, 20230131).Validate(), "valid range")
+	assert.NoError(t, mustRange(t, 20230101, 20230101).Validate(), "single day range")
+	assert.Error(t, mustRange(t, 20230131, 20230101).Validate(), "reversed range")
+
+	r := YMDRange{Start: YMDFlag{yyyymmdd: 20230229}, End: mustYMD(t, 20230301)}
+	assert.Error(t, r.Validate(), "invalid start")
+	r = YMDRange{Start: mustYMD(t, 20230201), End: YMDFlag{yyyymmdd: 20231301}}
+	assert.Error(t, r.Validate(), "invalid end")
+}
+
+func TestFindFirst(t *testing.T) {
+	isWeekend := func(ymd YMDFlag) bool {
+		weekday := ymd.AsTime().Weekday()
+		return weekday == time.Saturday || weekday == time.Sunday
+	}
+
+	// Jul 4 2023 is a Tuesday
+	found, ok := mustRange(t, 20230704, 20230731).FindFirst(isWeekend)
+	assert.True(t, ok)
+	assert.Equal(t, 20230708, found.GetYMD(), "first Saturday")
+
+	found, ok = mustRange(t, 20230709, 20230731).FindFirst(isWeekend)
+	assert.True(t, ok)
+	assert.Equal(t, 20230709, found.GetYMD(), "Start itself matches")
+
+	_, ok = mustRange(t, 20230704, 20230707).FindFirst(isWeekend)
+	assert.False(t, ok, "no weekend in range")
+
+	_, ok = mustRange(t, 20230101, 20231231).FindFirst(func(YMDFlag) bool { return false })
+	assert.False(t, ok, "predicate matches nothing")
+}
+
+func TestCount(t *testing.T) {
+	isWeekday := func(ymd YMDFlag) bool {
+		weekday := ymd.AsTime().Weekday()
+		return weekday != time.Saturday && weekday != time.Sunday
+	}
+	isMonthEnd := func(ymd YMDFlag) bool {
+		return ymd.AsTime().AddDate(0, 0, 1).Day() == 1
+	}
+
+	// July 2023 has 21 weekdays
+	assert.Equal(t, 21, mustRange(t, 20230701, 20230731).Count(isWeekday))
+	assert.Equal(t, 31, mustRange(t, 20230701, 20230731).Count(func(YMDFlag) bool { return true }))
+
+	// Jan 15 2024 through Apr 30 2024 contains four month ends, including leap Feb 29
+	assert.Equal(t, 4, mustRange(t, 20240115, 20240430).Count(isMonthEnd))
+	assert.Equal(t, 3, mustRange(t, 20240115, 20240429).Count(isMonthEnd))
+	assert.Equal(t, 0, mustRange(t, 20240101, 20240130).Count(isMonthEnd))
+}
+
+func TestMapRange(t *testing.T) {
+	r := mustRange(t, 20230730, 20230802)
+	paths := MapRange(r, func(ymd YMDFlag) string { return FormatDirPath(ymd, '/') })
+	assert.Equal(t, []string{"2023/07/30", "2023/07/31", "2023/08/01", "2023/08/02"}, paths)
+
+	ints := MapRange(r, YMDFlag.GetYMD)
+	assert.Equal(t, []int{20230730, 20230731, 20230801, 20230802}, ints)
+}
+
+func TestClampTo(t *testing.T) {
+	bound := mustRange(t, 20230101, 20231231)
+
+	clamped, ok := mustRange(t, 20221215, 20230115).ClampTo(bound)
+	assert.True(t, ok, "partly outside")
+	assert.Equal(t, mustRange(t, 20230101, 20230115), clamped)
+
+	clamped, ok = mustRange(t, 20230301, 20230331).ClampTo(bound)
+	assert.True(t, ok, "fully inside")
+	assert.Equal(t, mustRange(t, 20230301, 20230331), clamped)
+
+	clamped, ok = mustRange(t, 20221201, 20240131).ClampTo(bound)
+	assert.True(t, ok, "fully covering")
+	assert.Equal(t, bound, clamped)
+
+	_, ok = mustRange(t, 20240101, 20240131).ClampTo(bound)
+	assert.False(t, ok, "disjoint")
+
+	clamped, ok = mustRange(t, 20230115, 20221215).ClampTo(bound)
+	assert.True(t, ok, "reversed range is ordered")
+	assert.Equal(t, mustRange(t, 20230101, 20230115), clamped)
+	clamped, ok = mustRange(t, 20230301, 20230331).ClampTo(mustRange(t, 20231231, 20230101))
+	assert.True(t, ok, "reversed bound is ordered")
+	assert.Equal(t, mustRange(t, 20230301, 20230331), clamped)
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	clamped, ok = YMDRange{Start: mustYMD(t, 20230601), End: mustYMDIn(t, 0, time.UTC)}.ClampTo(bound)
+	assert.True(t, ok, "nil End is today")
+	assert.Equal(t, []int{20230601, 20230704}, []int{clamped.Start.GetYMD(), clamped.End.GetYMD()})
+	clamped, ok = mustRange(t, 20230601, 20231015).ClampTo(YMDRange{Start: mustYMDIn(t, 0, time.UTC), End: mustYMD(t, 20231231)})
+	assert.True(t, ok, "nil bound Start is today")
+	assert.Equal(t, []int{20230704, 20231015}, []int{clamped.Start.GetYMD(), clamped.End.GetYMD()})
+	_, ok = mustRange(t, 20230801, 20230831).ClampTo(YMDRange{Start: mustYMD(t, 20230101), End: mustYMDIn(t, 0, time.UTC)})
+	assert.False(t, ok, "open bound ends today")
+}
+
+func TestOrdered(t *testing.T) {
+	assert.Equal(t, mustRange(t, 20230101, 20230131), mustRange(t, 20230131, 20230101).Ordered(), "reversed is swapped")
+	assert.Equal(t, mustRange(t, 20230101, 20230131), mustRange(t, 20230101, 20230131).Ordered(), "ordered passes through")
+	assert.Equal(t, mustRange(t, 20230101, 20230101), mustRange(t, 20230101, 20230101).Ordered(), "single day")
+
+	loc := time.FixedZone("UTC+1", 60*60)
+	r := YMDRange{Start: mustYMDIn(t, 20230131, loc), End: mustYMD(t, 20230101)}
+	assert.Equal(t, loc, r.Ordered().End.Location(), "endpoints keep their locations")
+	assert.NoError(t, r.Ordered().Validate())
+}
+
+func TestRangeDays(t *testing.T) {
+	r := mustRange(t, 20230704, 20230704)
+	assert.Equal(t, 1, r.Len(), "single day")
+	assert.Equal(t, []int{20230704}, ymdInts(r.Days()), "single day")
+
+	r = mustRange(t, 20230130, 20230302)
+	assert.Equal(t, 32, r.Len(), "multi-month")
+	days := r.Days()
+	assert.Len(t, days, 32)
+	assert.Equal(t, 20230130, days[0].GetYMD())
+	assert.Equal(t, 20230201, days[2].GetYMD())
+	assert.Equal(t, 20230228, days[29].GetYMD())
+	assert.Equal(t, 20230302, days[31].GetYMD())
+
+	r = mustRange(t, 20240227, 20240302)
+	assert.Equal(t, 5, r.Len(), "leap year")
+	assert.Equal(t, []int{20240227, 20240228, 20240229, 20240301, 20240302}, ymdInts(r.Days()))
+
+	r = mustRange(t, 20231230, 20240102)
+	assert.Equal(t, []int{20231230, 20231231, 20240101, 20240102}, ymdInts(r.Days()), "year boundary")
+
+	r = mustRange(t, 20230302, 20230227)
+	assert.Equal(t, 4, r.Len(), "reversed")
+	assert.Equal(t, []int{20230302, 20230301, 20230228, 20230227}, ymdInts(r.Days()), "reversed iterates backward")
+
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	r = YMDRange{Start: mustYMDIn(t, 20230101, loc), End: mustYMD(t, 20230103)}
+	for _, day := range r.Days() {
+		assert.Equal(t, loc, day.Location(), "dates have the start location")
+	}
+}
+
+func TestRandomDate(t *testing.T) {
+	r := mustRange(t, 20230101, 20231231)
+	draw := func(seed int64) []int {
+		rng := rand.New(rand.NewSource(seed))
+		var result []int
+		for i := 0; i < 20; i++ {
+			result = append(result, r.RandomDate(rng).GetYMD())
+		}
+		return result
+	}
+
+	first, second := draw(42), draw(42)
+	assert.Equal(t, first, second, "same seed is reproducible")
+	assert.NotEqual(t, first, draw(43), "different seed differs")
+	for _, yyyymmdd := range first {
+		assert.True(t, yyyymmdd >= 20230101 && yyyymmdd <= 20231231, "in range %d", yyyymmdd)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	assert.Equal(t, 20230704, mustRange(t, 20230704, 20230704).RandomDate(rng).GetYMD(), "single day")
+	for i := 0; i < 20; i++ {
+		yyyymmdd := mustRange(t, 20230105, 20230101).RandomDate(rng).GetYMD()
+		assert.True(t, yyyymmdd >= 20230101 && yyyymmdd <= 20230105, "reversed in range %d", yyyymmdd)
+	}
+}
+
+func TestDaysOnWeekdays(t *testing.T) {
+	// Mon Jul 3 2023 through Sun Jul 16 2023
+	r := mustRange(t, 20230703, 20230716)
+	result := r.DaysOnWeekdays(time.Tuesday, time.Thursday)
+	assert.Equal(t, []int{20230704, 20230706, 20230711, 20230713}, ymdInts(result))
+
+	result = r.DaysOnWeekdays(time.Thursday, time.Tuesday, time.Tuesday)
+	assert.Equal(t, []int{20230704, 20230706, 20230711, 20230713}, ymdInts(result), "order and duplicates of weekdays do not matter")
+
+	assert.Empty(t, r.DaysOnWeekdays(), "no weekdays")
+	assert.Equal(t, []int{20230709, 20230716}, ymdInts(r.DaysOnWeekdays(time.Sunday)))
+}
+
+func TestBoundingRange(t *testing.T) {
+	flags := []YMDFlag{mustYMD(t, 20230704), mustYMD(t, 20221225), mustYMD(t, 20240229), mustYMD(t, 20230101)}
+	r, ok := BoundingRange(flags)
+	assert.True(t, ok)
+	assert.Equal(t, mustRange(t, 20221225, 20240229), r)
+
+	r, ok = BoundingRange(flags[:1])
+	assert.True(t, ok, "single date")
+	assert.Equal(t, mustRange(t, 20230704, 20230704), r)
+
+	_, ok = BoundingRange(nil)
+	assert.False(t, ok, "empty slice")
+}
+
+func TestSQLBounds(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	r := YMDRange{Start: mustYMDIn(t, 20231230, newYork), End: mustYMDIn(t, 20231231, time.UTC)}
+	start, endExclusive := r.SQLBounds()
+	assert.Equal(t, time.Date(2023, 12, 30, 0, 0, 0, 0, newYork), start)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, newYork), endExclusive, "day after End, in the location of Start")
+
+	start, endExclusive = mustRange(t, 20240229, 20240229).SQLBounds()
+	assert.Equal(t, 24*time.Hour, endExclusive.Sub(start), "single day")
+}
+
+func TestOccurrencesOfDay(t *testing.T) {
+	r := mustRange(t, 20230101, 20230430)
+	assert.Equal(t, []int{20230131, 20230331}, ymdInts(r.OccurrencesOfDay(31)), "February and April are skipped")
+	assert.Equal(t, []int{20230130, 20230330, 20230430}, ymdInts(r.OccurrencesOfDay(30)))
+	assert.Equal(t, []int{20230101, 20230201, 20230301, 20230401}, ymdInts(r.OccurrencesOfDay(1)))
+
+	assert.Equal(t, []int{20240129, 20240229, 20240329}, ymdInts(mustRange(t, 20240101, 20240331).OccurrencesOfDay(29)), "leap day")
+	assert.Equal(t, []int{20230215}, ymdInts(mustRange(t, 20230116, 20230314).OccurrencesOfDay(15)), "partial months at the ends")
+	assert.Equal(t, []int{20230131, 20230331}, ymdInts(mustRange(t, 20230430, 20230101).OccurrencesOfDay(31)), "reversed is ascending")
+	assert.Equal(t, []int{20231231, 20240131}, ymdInts(mustRange(t, 20231201, 20240131).OccurrencesOfDay(31)), "across years")
+
+	assert.Empty(t, mustRange(t, 20230201, 20230228).OccurrencesOfDay(30))
+	assert.Nil(t, r.OccurrencesOfDay(0))
+	assert.Nil(t, r.OccurrencesOfDay(32))
+}
+
+func TestDirPathsInRange(t *testing.T) {
+	paths := DirPathsInRange(mustYMD(t, 20230629), mustYMD(t, 20230702), '/')
+	assert.Equal(t, []string{"2023/06/29", "2023/06/30", "2023/07/01", "2023/07/02"}, paths, "across a month boundary")
+
+	paths = DirPathsInRange(mustYMD(t, 20231231), mustYMD(t, 20231231), '\\')
+	assert.Equal(t, []string{`2023\12\31`}, paths, "single day")
+
+	paths = DirPathsInRange(mustYMD(t, 20240301), mustYMD(t, 20240228), '-')
+	assert.Equal(t, []string{"2024-03-01", "2024-02-29", "2024-02-28"}, paths, "reversed")
+
+	// 2023-07-04 22:00 UTC is already Jul 5 in Tokyo
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	paths = DirPathsInRange(mustYMDIn(t, 0, tokyo), mustYMD(t, 20230706), '/')
+	assert.Equal(t, []string{"2023/07/05", "2023/07/06"}, paths, "nil start is today in its location")
+}
+
+func TestRangeStep(t *testing.T) {
+	days, err := RangeStep(mustYMD(t, 20230601), mustYMD(t, 20230630), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230601, 20230608, 20230615, 20230622, 20230629}, ymdInts(days), "weekly over 30 days")
+
+	days, err = RangeStep(mustYMD(t, 20230601), mustYMD(t, 20230629), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230601, 20230608, 20230615, 20230622, 20230629}, ymdInts(days), "step lands exactly on end")
+
+	days, err = RangeStep(mustYMD(t, 20230601), mustYMD(t, 20230628), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230601, 20230608, 20230615, 20230622}, ymdInts(days), "step overshooting end is omitted")
+
+	days, err = RangeStep(mustYMD(t, 20240305), mustYMD(t, 20240224), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20240305, 20240229, 20240224}, ymdInts(days), "reversed across a leap day")
+
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	days, err = RangeStep(mustYMDIn(t, 20230704, tokyo), mustYMD(t, 20230704), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230704}, ymdInts(days), "single day")
+	assert.Equal(t, tokyo, days[0].Location(), "location of start")
+
+	for _, step := range []int{0, -7} {
+		_, err = RangeStep(mustYMD(t, 20230601), mustYMD(t, 20230630), step)
+		assert.Error(t, err, "step %d", step)
+	}
+}
+
+func TestMonthPrefixesInRange(t *testing.T) {
+	prefixes := MonthPrefixesInRange(mustYMD(t, 20221115), mustYMD(t, 20230302), "logs", '/')
+	assert.Equal(t, []string{"logs/2022/11", "logs/2022/12", "logs/2023/01", "logs/2023/02", "logs/2023/03"},
+		prefixes, "one per month across a year boundary")
+
+	prefixes = MonthPrefixesInRange(mustYMD(t, 20230701), mustYMD(t, 20230731), "logs/", '/')
+	assert.Equal(t, []string{"logs/2023/07"}, prefixes, "deduped within a month, trailing separator trimmed")
+
+	prefixes = MonthPrefixesInRange(mustYMD(t, 20230205), mustYMD(t, 20221230), "", '\\')
+	assert.Equal(t, []string{`2023\02`, `2023\01`, `2022\12`}, prefixes, "reversed without root")
+
+	// 2023-07-31 22:00 UTC is already Aug 1 in Tokyo
+	setNow(t, time.Date(2023, time.July, 31, 22, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	prefixes = MonthPrefixesInRange(mustYMD(t, 20230715), mustYMDIn(t, 0, tokyo), "s3", '/')
+	assert.Equal(t, []string{"s3/2023/07", "s3/2023/08"}, prefixes, "nil end is today in its location")
+}
+
+func TestContains(t *testing.T) {
+	r := mustRange(t, 20230701, 20230731)
+	for _, yyyymmdd := range []int{20230701, 20230715, 20230731} {
+		assert.True(t, r.Contains(mustYMD(t, yyyymmdd)), "%d", yyyymmdd)
+	}
+	for _, yyyymmdd := range []int{20230630, 20230801} {
+		assert.False(t, r.Contains(mustYMD(t, yyyymmdd)), "%d", yyyymmdd)
+	}
+	assert.True(t, mustRange(t, 20230731, 20230701).Contains(mustYMD(t, 20230701)), "reversed")
+	assert.True(t, r.Contains(mustYMDIn(t, 20230701, time.FixedZone("UTC+14", 14*60*60))), "location is ignored")
+}
+
+func TestContainsExclusive(t *testing.T) {
+	r := mustRange(t, 20230701, 20230731)
+	assert.False(t, r.ContainsExclusive(mustYMD(t, 20230701)), "start")
+	assert.False(t, r.ContainsExclusive(mustYMD(t, 20230731)), "end")
+	assert.True(t, r.ContainsExclusive(mustYMD(t, 20230702)), "interior")
+	assert.True(t, r.ContainsExclusive(mustYMD(t, 20230730)), "interior")
+	assert.False(t, r.ContainsExclusive(mustYMD(t, 20230801)), "outside")
+	assert.True(t, mustRange(t, 20230731, 20230701).ContainsExclusive(mustYMD(t, 20230715)), "reversed")
+	assert.False(t, mustRange(t, 20230704, 20230704).ContainsExclusive(mustYMD(t, 20230704)), "single day")
+	assert.False(t, mustRange(t, 20230704, 20230705).ContainsExclusive(mustYMD(t, 20230704)), "adjacent days")
+}
+
+func TestBetween(t *testing.T) {
+	start, end := mustYMD(t, 20230701), mustYMD(t, 20230731)
+	assert.True(t, mustYMD(t, 20230701).Between(start, end), "start")
+	assert.True(t, mustYMD(t, 20230731).Between(start, end), "end")
+	assert.True(t, mustYMD(t, 20230715).Between(start, end))
+	assert.False(t, mustYMD(t, 20230630).Between(start, end))
+	assert.False(t, mustYMD(t, 20230801).Between(start, end))
+	assert.True(t, mustYMD(t, 20230731).Between(end, start), "reversed")
+	assert.True(t, mustYMD(t, 20230701).Between(end, start), "reversed")
+
+	assert.True(t, mustYMD(t, 20230701).BetweenHalfOpen(start, end), "start is included")
+	assert.False(t, mustYMD(t, 20230731).BetweenHalfOpen(start, end), "end is excluded")
+	assert.True(t, mustYMD(t, 20230730).BetweenHalfOpen(start, end))
+	assert.True(t, mustYMD(t, 20230701).BetweenHalfOpen(end, start), "reversed includes the earlier endpoint")
+	assert.False(t, mustYMD(t, 20230731).BetweenHalfOpen(end, start), "reversed excludes the later endpoint")
+	assert.False(t, start.BetweenHalfOpen(start, start), "empty interval")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	assert.True(t, mustYMDIn(t, 0, time.UTC).Between(start, end), "nil is today")
+	assert.True(t, mustYMD(t, 20230704).Between(mustYMDIn(t, 0, time.UTC), end), "nil is today")
+	assert.False(t, mustYMD(t, 20230704).BetweenHalfOpen(start, mustYMDIn(t, 0, time.UTC)), "nil is today")
+}
@@ -0,0 +1,115 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// YMDRangeFlag is a flag.Value holding a YMDRange, specified as `START..END`, such as `--range 20230101..20230131`.
+//
+// Each endpoint is parsed as YMDFlag.Set does, keeping any location already set on it.
+// A single date without `..` is a one-day range.  An empty endpoint is nil, which resolves to today,
+// so `20230101..` runs through today.  Set rejects ranges whose START is after END.
+type YMDRangeFlag struct {
+	YMDRange
+}
+
+// Type implements pflag.Value.Type.  Returns "YMDRangeFlag".
+func (*YMDRangeFlag) Type() string {
+	return "YMDRangeFlag"
+}
+
+// String implements the flag.Value interface, returning the range as `START..END`, as YMDRange.MarshalText does.
+// If both endpoints are nil, it returns the empty string.  Nil endpoints are not resolved to today.
+func (r *YMDRangeFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	text, _ := r.YMDRange.MarshalText()
+	return string(text)
+}
+
+// Set implements the flag.Value interface, parsing `START..END` or a single date, as YMDRange.UnmarshalText does,
+// except that each endpoint is parsed as YMDFlag.Set does, so relative dates are accepted if AllowRelativeDates is true.
+// If either endpoint is invalid, or START is after END, the range is unchanged.
+func (r *YMDRangeFlag) Set(value string) error {
+	return r.YMDRange.unmarshalText(value, AllowRelativeDates)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// RelativeRangeFlag is a flag.Value holding a YMDRange ending today, specified as `<n><unit>`,
+// such as `--last 7d`.  The unit is one of `d`, `w`, `m`, or `y` for days, weeks, months, or years.
+//
+// Set resolves the range immediately: End is today in End's location, as determined by NowFunc,
+// and the inclusive range spans the last `n` units, so `7d` is 7 days and `1d` is just today.
+// Start is `n` units before the day after End, clamping the day to the end of the target month rather than
+// rolling over as AddDate does, so `1m` on March 31 starts on March 1, and `1y` on 2024-02-29 starts on 2023-03-01.
+// Locations may be set on the endpoints of Range before Set is called, and are kept.
+//
+// Range is a field rather than embedded, so that its methods cannot change the range without updating the token.
+type RelativeRangeFlag struct {
+	Range YMDRange // the resolved range, from Start through today inclusive
+	token string
+}
+
+// Type implements pflag.Value.Type.  Returns "RelativeRangeFlag".
+func (*RelativeRangeFlag) Type() string {
+	return "RelativeRangeFlag"
+}
+
+// String implements the flag.Value interface, returning the token passed to Set, such as `7d`.
+// If the flag is unset, it returns the empty string.
+func (r *RelativeRangeFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.token
+}
+
+// Set implements the flag.Value interface, parsing `<n><unit>` where `n` is a positive integer.
+// If the value is invalid, the range is unchanged.
+func (r *RelativeRangeFlag) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return fmt.Errorf("expect relative range of format <n><unit> with unit d, w, m, or y: %w", ErrBadFormat)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 || !isInt(value[:len(value)-1]) {
+		return fmt.Errorf("expect relative range of format <n><unit> with a positive count: %w", ErrBadFormat)
+	}
+	var years, months, days int
+	switch value[len(value)-1] {
+	case 'd':
+		days = n
+	case 'w':
+		days = 7 * n
+	case 'm':
+		months = n
+	case 'y':
+		years = n
+	default:
+		return fmt.Errorf("expect relative range unit of d, w, m, or y: %w", ErrBadFormat)
+	}
+	end := r.Range.End
+	end.yyyymmdd = end.today()
+	// the range is inclusive of End, so it runs back n units from the day after
+	startTime := YMDToTime(end.yyyymmdd, time.UTC).AddDate(0, 0, 1-days)
+	if years != 0 || months != 0 {
+		year, month, day := YMDFromTime(startTime)
+		target := time.Date(year-years, time.Month(month-months), 1, 0, 0, 0, 0, time.UTC)
+		startTime = target.AddDate(0, 0, min(day, daysInMonth(target.Year(), int(target.Month())))-1)
+	}
+	startYMD, err := TimeToYMDChecked(startTime)
+	if err != nil {
+		return fmt.Errorf("failed to validate range start %w", err)
+	}
+	start := r.Range.Start
+	start.yyyymmdd = startYMD
+	r.Range, r.token = YMDRange{Start: start, End: end}, value
+	return nil
+}
@@ -0,0 +1,130 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"encoding"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYMDRangeFlag(t *testing.T) {
+	var r YMDRangeFlag
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.VarP(&r, "range", "r", "START..END dates")
+	assert.NoError(t, fs.Parse([]string{"--range", "20230101..20230131"}))
+	assert.Equal(t, mustRange(t, 20230101, 20230131), r.YMDRange)
+	assert.Equal(t, "20230101..20230131", r.String())
+	assert.Equal(t, 31, r.Len(), "YMDRange methods are promoted")
+
+	assert.NoError(t, r.Set("2024-02-29"), "single date")
+	assert.Equal(t, mustRange(t, 20240229, 20240229), r.YMDRange)
+	assert.Equal(t, "20240229..20240229", r.String())
+
+	assert.NoError(t, r.Set("20230704..20230704"), "same start and end")
+	assert.Equal(t, 1, r.Len())
+
+	assert.Error(t, r.Set("20230131..20230101"), "inverted range")
+	assert.Error(t, r.Set("20230101..20230230"), "invalid end")
+	assert.Error(t, r.Set("bad..20230101"), "invalid start")
+	assert.Error(t, r.Set("20230101...20230105"), "too many dots")
+	assert.Equal(t, mustRange(t, 20230704, 20230704), r.YMDRange, "unchanged on error")
+
+	var empty YMDRangeFlag
+	assert.Equal(t, "", empty.String())
+	assert.Equal(t, "YMDRangeFlag", empty.Type())
+}
+
+func TestYMDRangeFlagRelative(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+
+	r := YMDRangeFlag{YMDRange{Start: mustYMDIn(t, 0, time.UTC), End: mustYMDIn(t, 0, time.UTC)}}
+	assert.NoError(t, r.Set("-7..yesterday"))
+	assert.Equal(t, 20230627, r.Start.GetYMD())
+	assert.Equal(t, 20230703, r.End.GetYMD())
+	assert.Equal(t, time.UTC, r.Start.Location(), "locations are kept")
+
+	assert.NoError(t, r.Set("20230701.."), "open end is today")
+	assert.True(t, r.End.IsZero())
+	assert.Equal(t, "20230701..", r.String())
+	assert.Equal(t, 4, r.Len())
+	assert.Error(t, r.Set("20230801.."), "after today")
+}
+
+func TestRelativeRangeFlag(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+
+	r := RelativeRangeFlag{Range: YMDRange{Start: mustYMDIn(t, 0, time.UTC), End: mustYMDIn(t, 0, time.UTC)}}
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Var(&r, "last", "relative range such as 7d")
+	assert.NoError(t, fs.Parse([]string{"--last", "7d"}))
+	assert.Equal(t, 20230628, r.Range.Start.GetYMD())
+	assert.Equal(t, 20230704, r.Range.End.GetYMD())
+	assert.Equal(t, "7d", r.String(), "renders the original token")
+	assert.Equal(t, time.UTC, r.Range.Start.Location(), "locations are kept")
+	assert.Equal(t, 7, r.Range.Len(), "7d is 7 days")
+
+	cases := map[string]int{
+		"1d": 20230704,
+		"2w": 20230621,
+		"1m": 20230605,
+		"1y": 20220705,
+	}
+	for token, start := range cases {
+		assert.NoError(t, r.Set(token), token)
+		assert.Equal(t, start, r.Range.Start.GetYMD(), token)
+		assert.Equal(t, 20230704, r.Range.End.GetYMD(), token)
+		assert.Equal(t, token, r.String())
+	}
+	assert.NoError(t, r.Set("2w"))
+	assert.Equal(t, 14, r.Range.Len(), "2w is 14 days")
+
+	// 1m on March 31 is all of March, not from the day after a normalized February 31
+	setNow(t, time.Date(2023, time.March, 31, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, r.Set("1m"))
+	assert.Equal(t, 20230301, r.Range.Start.GetYMD())
+	assert.Equal(t, 31, r.Range.Len())
+	// 1m on March 30 clamps the day after, March 31, to February 28
+	setNow(t, time.Date(2023, time.March, 30, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, r.Set("1m"))
+	assert.Equal(t, 20230228, r.Range.Start.GetYMD())
+
+	// 1y on a leap day is the 366 days after 2023-02-28, and 1y on the following February 28 is 365 days
+	setNow(t, time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, r.Set("1y"))
+	assert.Equal(t, 20230301, r.Range.Start.GetYMD())
+	assert.Equal(t, 20240229, r.Range.End.GetYMD())
+	assert.Equal(t, 366, r.Range.Len())
+	setNow(t, time.Date(2025, time.February, 28, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, r.Set("1y"))
+	assert.Equal(t, 20240301, r.Range.Start.GetYMD())
+	assert.Equal(t, 365, r.Range.Len())
+
+	// today is in the End's location, which is already Jul 5 in Tokyo
+	east := RelativeRangeFlag{Range: YMDRange{End: mustYMDIn(t, 0, tokyo)}}
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	assert.NoError(t, east.Set("2d"))
+	assert.Equal(t, 20230704, east.Range.Start.GetYMD())
+	assert.Equal(t, 20230705, east.Range.End.GetYMD())
+	assert.Equal(t, tokyo, east.Range.End.Location())
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, r.Set("1y"))
+	for _, token := range []string{"7x", "7", "d", "0d", "-7d", "+7d", "7D", "", "1.5w"} {
+		assert.ErrorIs(t, r.Set(token), ErrBadFormat, token)
+	}
+	assert.ErrorIs(t, r.Set("20000y"), ErrOutOfRange)
+	assert.Equal(t, "1y", r.String(), "unchanged on error")
+	assert.Equal(t, 20220705, r.Range.Start.GetYMD(), "unchanged on error")
+
+	_, isTextUnmarshaler := any(&r).(encoding.TextUnmarshaler)
+	assert.False(t, isTextUnmarshaler, "the token cannot be bypassed")
+
+	var empty RelativeRangeFlag
+	assert.Equal(t, "", empty.String())
+	assert.Equal(t, "RelativeRangeFlag", empty.Type())
+}
@@ -0,0 +1,49 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+// YMDSet is a set of dates, keyed by their integral `yyyymmdd`, for membership tests such as allow-lists
+// of trading days.  Membership ignores location.  It is also a HolidayCalendar, and HolidaySet is an alias of it.
+type YMDSet map[int]struct{}
+
+// NewYMDSet creates a new YMDSet of the given dates.  Nil YMDFlags are ignored.
+func NewYMDSet(dates ...YMDFlag) YMDSet {
+	set := make(YMDSet, len(dates))
+	for _, ymd := range dates {
+		set.Add(ymd)
+	}
+	return set
+}
+
+// Add adds the YMDFlag's date to the YMDSet.  A nil YMDFlag is ignored.
+func (set YMDSet) Add(ymd YMDFlag) {
+	if !ymd.IsZero() {
+		set[ymd.yyyymmdd] = struct{}{}
+	}
+}
+
+// AddRange adds every date from `start` through `end` inclusive to the YMDSet, in either order.
+// Nil endpoints are resolved to today.
+func (set YMDSet) AddRange(start, end YMDFlag) {
+	YMDRange{Start: start, End: end}.each(func(ymd YMDFlag) bool {
+		set[ymd.yyyymmdd] = struct{}{}
+		return true
+	})
+}
+
+// Contains returns true if the YMDFlag's date is in the YMDSet.  A nil YMDSet contains no dates.
+func (set YMDSet) Contains(ymd YMDFlag) bool {
+	_, ok := set[ymd.yyyymmdd]
+	return ok
+}
+
+// IsHoliday implements HolidayCalendar, returning true if the YMDFlag's date is in the YMDSet, as Contains does.
+// A nil YMDSet contains no holidays.
+func (set YMDSet) IsHoliday(ymd YMDFlag) bool {
+	return set.Contains(ymd)
+}
+
+// Len returns the number of distinct dates in the YMDSet.
+func (set YMDSet) Len() int {
+	return len(set)
+}
@@ -0,0 +1,53 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYMDSet(t *testing.T) {
+	set := NewYMDSet(mustYMD(t, 20230704), mustYMD(t, 20231225), YMDFlag{})
+	assert.Equal(t, 2, set.Len(), "nil flags are ignored")
+	set.Add(mustYMDIn(t, 20230904, time.UTC))
+	set.Add(mustYMD(t, 20230904))
+	assert.Equal(t, 3, set.Len(), "duplicates collapse")
+
+	assert.True(t, set.Contains(mustYMD(t, 20230704)))
+	assert.True(t, set.Contains(mustYMDIn(t, 20231225, time.UTC)), "location is ignored")
+	assert.False(t, set.Contains(mustYMD(t, 20230705)))
+	assert.False(t, YMDSet(nil).Contains(mustYMD(t, 20230704)), "nil set")
+	assert.Equal(t, 0, YMDSet(nil).Len())
+}
+
+func TestYMDSetAddRange(t *testing.T) {
+	set := NewYMDSet()
+	set.AddRange(mustYMD(t, 20230630), mustYMD(t, 20230704))
+	assert.Equal(t, 5, set.Len())
+	assert.True(t, set.Contains(mustYMD(t, 20230701)), "month boundary")
+	assert.False(t, set.Contains(mustYMD(t, 20230705)))
+
+	set.AddRange(mustYMD(t, 20230706), mustYMD(t, 20230703))
+	assert.Equal(t, 7, set.Len(), "reversed range, overlapping dates collapse")
+	set.AddRange(mustYMD(t, 20230704), mustYMD(t, 20230704))
+	assert.Equal(t, 7, set.Len(), "single day already present")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	set = NewYMDSet()
+	set.AddRange(mustYMDIn(t, 20230702, time.UTC), mustYMDIn(t, 0, time.UTC))
+	assert.Equal(t, 3, set.Len(), "nil end is today")
+}
+
+func TestYMDSetIsHolidaySet(t *testing.T) {
+	set := NewYMDSet(mustYMD(t, 20230704))
+	var cal HolidayCalendar = set
+	assert.True(t, cal.IsHoliday(mustYMD(t, 20230704)))
+	assert.False(t, cal.IsHoliday(mustYMD(t, 20230705)))
+
+	var holidays HolidaySet = set
+	assert.Equal(t, 4, BusinessDayCount(mustYMD(t, 20230703), mustYMD(t, 20230707), holidays), "interchangeable")
+	assert.True(t, NewHolidaySet(mustYMD(t, 20231225)).Contains(mustYMD(t, 20231225)))
+}
@@ -0,0 +1,86 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strings"
+)
+
+// YMDFlagSlice is a flag.Value collecting repeated `YYYYMMDD` flags, such as `--date 20230101 --date 20230102`.
+//
+// Each call to Set appends the parsed dates, in order; a single value may also hold a comma-separated list.
+// Duplicates are kept.  Dates are parsed as YMDFlag.Set does, in local time, except that empty elements,
+// such as from a trailing comma, are rejected rather than appended as nil YMDFlags which would resolve to today.
+// It also implements the [pflag.SliceValue] interface.
+//
+// [pflag.SliceValue]: https://pkg.go.dev/github.com/spf13/pflag#SliceValue
+type YMDFlagSlice []YMDFlag
+
+// Type implements pflag.Value.Type.  Returns "YMDFlagSlice".
+func (*YMDFlagSlice) Type() string {
+	return "YMDFlagSlice"
+}
+
+// String implements the flag.Value interface, returning the dates as a comma-separated list.
+func (s *YMDFlagSlice) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.GetSlice(), ",")
+}
+
+// Set implements the flag.Value interface, appending the date or comma-separated dates in `value`.
+// If any of the dates is invalid or empty, none are appended, and the error wraps ErrBadFormat for an empty one.
+func (s *YMDFlagSlice) Set(value string) error {
+	parsed, err := parseYMDFlagList(strings.Split(value, ","))
+	if err != nil {
+		return err
+	}
+	*s = append(*s, parsed...)
+	return nil
+}
+
+// Append implements pflag.SliceValue, appending a single date, which may not be empty.
+func (s *YMDFlagSlice) Append(value string) error {
+	parsed, err := parseYMDFlagList([]string{value})
+	if err != nil {
+		return err
+	}
+	*s = append(*s, parsed...)
+	return nil
+}
+
+// Replace implements pflag.SliceValue, replacing the contents with the given dates.
+// If any of the dates is invalid, the contents are unchanged.
+func (s *YMDFlagSlice) Replace(values []string) error {
+	parsed, err := parseYMDFlagList(values)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// GetSlice implements pflag.SliceValue, returning the dates as `YYYYMMDD` strings.
+func (s *YMDFlagSlice) GetSlice() []string {
+	result := make([]string, len(*s))
+	for i, ymd := range *s {
+		result[i] = ymd.AsYMDString()
+	}
+	return result
+}
+
+// parseYMDFlagList parses each of the values as YMDFlag.Set does, rejecting those which result in a nil YMDFlag.
+func parseYMDFlagList(values []string) ([]YMDFlag, error) {
+	result := make([]YMDFlag, len(values))
+	for i, value := range values {
+		if err := result[i].Set(value); err != nil {
+			return nil, err
+		}
+		if result[i].IsZero() {
+			return nil, fmt.Errorf("date list element %d is empty: %w", i, ErrBadFormat)
+		}
+	}
+	return result, nil
+}
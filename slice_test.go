@@ -0,0 +1,48 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYMDFlagSlice(t *testing.T) {
+	var dates YMDFlagSlice
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.VarP(&dates, "date", "d", "YYYYMMDD dates")
+	assert.NoError(t, fs.Parse([]string{"--date", "20230101", "-d", "20230102", "--date=20230101"}))
+	assert.Equal(t, []int{20230101, 20230102, 20230101}, ymdInts(dates), "repeated flags append, keeping duplicates")
+	assert.Equal(t, "20230101,20230102,20230101", dates.String())
+
+	dates = nil
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Var(&dates, "date", "YYYYMMDD dates")
+	assert.NoError(t, fs.Parse([]string{"--date", "20230101,20230704,20240229"}))
+	assert.Equal(t, []int{20230101, 20230704, 20240229}, ymdInts(dates), "comma-separated list")
+
+	assert.Error(t, dates.Set("20230105,20230229"), "invalid date")
+	assert.Len(t, dates, 3, "nothing appended on error")
+	for _, value := range []string{"", "20230101,", ",20230101", "20230101,,20230102"} {
+		assert.ErrorIs(t, dates.Set(value), ErrBadFormat, "empty element in %q", value)
+	}
+	assert.Len(t, dates, 3, "nothing appended for empty elements")
+
+	var sliceValue pflag.SliceValue = &dates
+	assert.NoError(t, sliceValue.Append("20230705"))
+	assert.Equal(t, []string{"20230101", "20230704", "20240229", "20230705"}, sliceValue.GetSlice())
+	assert.NoError(t, sliceValue.Replace([]string{"20221231"}))
+	assert.Equal(t, []string{"20221231"}, sliceValue.GetSlice())
+	assert.Error(t, sliceValue.Replace([]string{"20221231", "bad"}))
+	assert.Equal(t, []string{"20221231"}, sliceValue.GetSlice(), "unchanged on error")
+	assert.Error(t, sliceValue.Append("bad"))
+	assert.ErrorIs(t, sliceValue.Append(""), ErrBadFormat)
+	assert.ErrorIs(t, sliceValue.Replace([]string{"20230101", " "}), ErrBadFormat)
+	assert.Equal(t, []string{"20221231"}, sliceValue.GetSlice(), "unchanged for empty elements")
+
+	var empty YMDFlagSlice
+	assert.Equal(t, "", empty.String())
+	assert.Equal(t, "YMDFlagSlice", empty.Type())
+}
@@ -0,0 +1,61 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// FuncMap returns functions for rendering dates in text/template and html/template, for example
+// `{{ .Date | ymd }}` or `{{ .Date | ymdFormat "Jan 2, 2006" }}`:
+//
+//	ymd        the date as `YYYYMMDD`, as AsYMDString
+//	ymdISO     the date as `YYYY-MM-DD`, as AsISOString
+//	ymdPath    the date as `YYYY/MM/DD`, as FormatDirPath with '/'
+//	ymdFormat  the date formatted with a Go time layout, as AsFormat; the layout is the first argument
+//
+// Each accepts a YMDFlag, a *YMDFlag, or an integral `YYYYMMDD`.  Nil YMDFlags are resolved to today,
+// without mutating them.  Use `html/template.FuncMap(ymdflag.FuncMap())` with html/template.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"ymd": func(date any) (string, error) {
+			ymd, err := templateYMDFlag(date)
+			return ymd.AsYMDString(), err
+		},
+		"ymdISO": func(date any) (string, error) {
+			ymd, err := templateYMDFlag(date)
+			return ymd.AsISOString(), err
+		},
+		"ymdPath": func(date any) (string, error) {
+			ymd, err := templateYMDFlag(date)
+			return FormatDirPath(ymd, '/'), err
+		},
+		"ymdFormat": func(layout string, date any) (string, error) {
+			ymd, err := templateYMDFlag(date)
+			return ymd.AsFormat(layout), err
+		},
+	}
+}
+
+// templateYMDFlag returns the resolved YMDFlag for a template function argument.
+func templateYMDFlag(date any) (YMDFlag, error) {
+	var ymd YMDFlag
+	switch v := date.(type) {
+	case YMDFlag:
+		ymd = v
+	case *YMDFlag:
+		if v == nil {
+			return YMDFlag{}, fmt.Errorf("expect YMDFlag or int, got nil *YMDFlag")
+		}
+		ymd = *v
+	case int:
+		var err error
+		if ymd, err = NewYMDFlagFromInt(v); err != nil {
+			return YMDFlag{}, err
+		}
+	default:
+		return YMDFlag{}, fmt.Errorf("expect YMDFlag or int, got %T", date)
+	}
+	return ymd.resolved(), nil
+}
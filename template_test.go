@@ -0,0 +1,63 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renderTemplate(t *testing.T, text string, data any) (string, error) {
+	t.Helper()
+	tmpl, err := template.New("test").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	return sb.String(), err
+}
+
+func TestFuncMap(t *testing.T) {
+	data := struct {
+		Date    YMDFlag
+		DatePtr *YMDFlag
+		Int     int
+	}{Date: mustYMD(t, 20230704), DatePtr: &YMDFlag{yyyymmdd: 20240229}, Int: 20231231}
+
+	cases := map[string]string{
+		"{{ .Date | ymd }}":                     "20230704",
+		"{{ .Date | ymdISO }}":                  "2023-07-04",
+		"{{ .Date | ymdPath }}":                 "2023/07/04",
+		`{{ .Date | ymdFormat "Jan 2, 2006" }}`: "Jul 4, 2023",
+		`{{ ymdFormat "2006.01.02" .DatePtr }}`: "2024.02.29",
+		"{{ .DatePtr | ymdPath }}":              "2024/02/29",
+		"{{ .Int | ymdISO }}":                   "2023-12-31",
+		"reports/{{ .Date | ymdPath }}/out.csv": "reports/2023/07/04/out.csv",
+	}
+	for text, expected := range cases {
+		out, err := renderTemplate(t, text, data)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, out, text)
+	}
+
+	_, err := renderTemplate(t, "{{ .Int | ymd }}", struct{ Int int }{20230230})
+	assert.Error(t, err, "invalid int")
+	_, err = renderTemplate(t, "{{ .S | ymd }}", struct{ S string }{"20230704"})
+	assert.ErrorContains(t, err, "expect YMDFlag or int, got string")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	out, err := renderTemplate(t, "{{ .Date | ymdISO }}", struct{ Date YMDFlag }{mustYMDIn(t, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-07-04", out, "nil is today")
+
+	tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(htmltemplate.FuncMap(FuncMap())).Parse("<p>{{ .Date | ymdISO }}</p>"))
+	var sb strings.Builder
+	assert.NoError(t, tmpl.Execute(&sb, data))
+	assert.Equal(t, "<p>2023-07-04</p>", sb.String(), "html/template")
+}
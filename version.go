@@ -0,0 +1,41 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"runtime/debug"
+)
+
+// modulePath is the module path of this package, used to find its version in build information.
+const modulePath = "github.com/neomantra/ymdflag"
+
+// version may be set at build time with:
+//
+//	go build -ldflags "-X github.com/neomantra/ymdflag.version=v1.2.3"
+var version = ""
+
+// Version returns the version of the ymdflag package in use, for diagnostics such as startup logs.
+// It is the version set at build time via ldflags, otherwise the module version recorded in the
+// binary's build information, otherwise "(devel)".
+func Version() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath && info.Main.Version != "" {
+			return info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path != modulePath {
+				continue
+			}
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			if dep.Version != "" {
+				return dep.Version
+			}
+		}
+	}
+	return "(devel)"
+}
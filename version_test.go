@@ -0,0 +1,18 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion(t *testing.T) {
+	assert.NotEmpty(t, Version())
+
+	saved := version
+	t.Cleanup(func() { version = saved })
+	version = "v1.2.3"
+	assert.Equal(t, "v1.2.3", Version(), "ldflags version takes precedence")
+}
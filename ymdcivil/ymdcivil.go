@@ -0,0 +1,39 @@
+// Package ymdcivil converts between ymdflag.YMDFlag and civil.Date from cloud.google.com/go/civil,
+// as used by BigQuery and Spanner.
+//
+// It is a separate module so that users of ymdflag who do not need civil dates do not depend on cloud.google.com/go.
+package ymdcivil
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/neomantra/ymdflag"
+)
+
+// FromCivil returns a YMDFlag with the date `d` in location `loc`, or local time if that is nil.
+// The zero civil.Date results in a nil YMDFlag with that location.
+// Returns a non-nil error if `d` is not a valid date, as from ymdflag.ValidateYMD.
+func FromCivil(d civil.Date, loc *time.Location) (ymdflag.YMDFlag, error) {
+	var ymd ymdflag.YMDFlag
+	if d != (civil.Date{}) {
+		var err error
+		if ymd, err = ymdflag.NewYMDFlagFromInt(10000*d.Year + 100*int(d.Month) + d.Day); err != nil {
+			return ymdflag.YMDFlag{}, err
+		}
+	}
+	ymd.SetLocation(loc)
+	return ymd, nil
+}
+
+// ToCivil returns the YMDFlag's date as a civil.Date.  The location is not retained.
+// A nil YMDFlag is not resolved to today, and results in the zero civil.Date.
+func ToCivil(ymd ymdflag.YMDFlag) civil.Date {
+	if ymd.IsZero() {
+		return civil.Date{}
+	}
+	year, month, day := ymd.AsYearMonthDay()
+	return civil.Date{Year: year, Month: time.Month(month), Day: day}
+}
@@ -0,0 +1,47 @@
+package ymdcivil
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/neomantra/ymdflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCivil(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ymd, err := FromCivil(civil.Date{Year: 2024, Month: time.February, Day: 29}, tokyo)
+	assert.NoError(t, err)
+	assert.Equal(t, 20240229, ymd.GetYMD())
+	assert.Equal(t, tokyo, ymd.Location())
+
+	ymd, err = FromCivil(civil.Date{}, tokyo)
+	assert.NoError(t, err)
+	assert.True(t, ymd.IsZero(), "zero civil.Date is nil")
+	assert.Equal(t, tokyo, ymd.Location())
+
+	_, err = FromCivil(civil.Date{Year: 2023, Month: time.February, Day: 29}, nil)
+	assert.ErrorIs(t, err, ymdflag.ErrOutOfRange, "not a leap year")
+}
+
+func TestToCivil(t *testing.T) {
+	ymd, err := ymdflag.NewYMDFlagFromInt(20230704)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := ToCivil(ymd)
+	assert.Equal(t, civil.Date{Year: 2023, Month: time.July, Day: 4}, d)
+	assert.Equal(t, "2023-07-04", d.String())
+
+	back, err := FromCivil(d, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, ymd.GetYMD(), back.GetYMD(), "round-trips through civil.Date")
+
+	assert.Equal(t, civil.Date{}, ToCivil(ymdflag.YMDFlag{}), "nil is not resolved to today")
+}
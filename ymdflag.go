@@ -3,6 +3,7 @@ package ymdflag
 // Copyright (c) 2023 Neomantra BV
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -17,20 +18,48 @@ import (
 // It stores an integral `yyyymmddd`.  The special value of 0 indicates that the value
 // is indeterminate and may be may be auto-populated by `UpdateNilToNow`, `AsTime`, or `AsTimeWithLoc`.
 //
+// It may also carry a location, which is used when resolving "today" and when converting to a `time.Time`.
+// A nil location means local time.  A YMDFlag created by `NewYMDFlagWithDefault` resolves a nil value
+// to its default date instead of today.
+//
+// Methods with a value receiver never mutate the YMDFlag, so they are safe for concurrent use.
+// Because `UpdateNilToNow`, `AsTime`, and `AsTimeWithLoc` populate a nil YMDFlag, concurrent calls
+// on a shared nil YMDFlag are a data race; call `Resolve` once before sharing it, such as in a config struct.
+//
 // [flag.Value interface]: https://pkg.go.dev/flag#Value
 // [flag]: https://pkg.go.dev/flag
 // [pflag]: https://pkg.go.dev/github.com/spf13/pflag
 type YMDFlag struct {
-	yyyymmdd int // internal yyyymmdd value, nil values might be mutated
+	yyyymmdd int            // internal yyyymmdd value, nil values might be mutated
+	loc      *time.Location // location of the date, nil means time.Local
+	def      int            // yyyymmdd a nil value resolves to, 0 means today
 }
 
+// NowFunc returns the current time, and is used wherever a YMDFlag resolves "today".
+// It defaults to `time.Now`, and may be replaced to pin the current date, for example in tests.
+var NowFunc = time.Now
+
 ///////////////////////////////////////////////////////////////////////////////
 
-// TODO: internal error consts how?
+// MinYear and MaxYear are the earliest and latest years supported, as 4-digit `YYYY` from 0001 through 9999.
+// Year 0000 is not a valid year.
+const (
+	MinYear = 1
+	MaxYear = 9999
+)
+
+// Errors returned while parsing and validating dates wrap one of these, so they may be checked with `errors.Is`.
+var (
+	// ErrBadFormat is wrapped by errors for strings which are not in an accepted date format.
+	ErrBadFormat = errors.New("bad format")
+	// ErrOutOfRange is wrapped by errors for values which are in an accepted format, but are not a valid date.
+	ErrOutOfRange = errors.New("out of range")
+)
 
 // YMDtoTime returns the Time corresponding to the YYYYMMDD in the specified location, without validating the argument.`
 // A value of 0 returns a Zero Time, independent of location.
 // A nil location implies local time.
+// Values outside the range accepted by ValidateYMD are normalized by `time.Date`; validate them first.
 func YMDToTime(yyyymmdd int, loc *time.Location) time.Time {
 	if yyyymmdd == 0 {
 		return time.Time{}
@@ -44,13 +73,56 @@ func YMDToTime(yyyymmdd int, loc *time.Location) time.Time {
 	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
 }
 
-// TimeToYMD returns the YYYYMMDD for the time.Time in that Time's location.
-// A zero time returns a 0 value.
+// TimeToYMD returns the YYYYMMDD for the time.Time in that Time's location.  A zero time returns a 0 value.
+// Note that the zero time is midnight of 0001-01-01 UTC, so that instant also returns 0.
+// A time whose year is outside MinYear through MaxYear saturates to 00010101 or 99991231, rather than returning 0,
+// which is the nil sentinel that resolves to today.  Use TimeToYMDChecked to detect such times.
 func TimeToYMD(t time.Time) int {
 	if t.IsZero() {
 		return 0
-	} else {
-		return 10000*t.Year() + 100*int(t.Month()) + t.Day()
+	}
+	return saturatedYMD(t)
+}
+
+// TimeToYMDChecked returns the YYYYMMDD for the time.Time in that Time's location, as TimeToYMD does,
+// but returns a non-nil error wrapping ErrOutOfRange if its year is outside MinYear through MaxYear.
+// A zero time returns a 0 value and no error.
+func TimeToYMDChecked(t time.Time) (int, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+	year, month, day := YMDFromTime(t)
+	if year < MinYear || year > MaxYear {
+		return 0, fmt.Errorf("time year %d is not supported, years are %04d through %04d: %w", year, MinYear, MaxYear, ErrOutOfRange)
+	}
+	return 10000*year + 100*month + day, nil
+}
+
+// YMDFromTime returns the year, month, and day of the time.Time in that Time's location.
+// It calls `t.Date()` once, rather than computing the date three times with `Year`, `Month`, and `Day`.
+// Unlike TimeToYMD, years outside MinYear through MaxYear are returned as-is.
+func YMDFromTime(t time.Time) (year, month, day int) {
+	y, m, d := t.Date()
+	return y, int(m), d
+}
+
+// civilToYMD returns the YYYYMMDD for the time.Time in that Time's location, without TimeToYMD's checks,
+// so that date arithmetic through the zero time or out of range remains distinguishable from a nil date.
+func civilToYMD(t time.Time) int {
+	year, month, day := YMDFromTime(t)
+	return 10000*year + 100*month + day
+}
+
+// saturatedYMD returns the civilToYMD of the time.Time, clamped to 00010101 through 99991231.
+// Unlike TimeToYMD, the zero time is 00010101 in UTC, so date arithmetic never produces the nil sentinel.
+func saturatedYMD(t time.Time) int {
+	switch year, month, day := YMDFromTime(t); {
+	case year < MinYear:
+		return 10000*MinYear + 101
+	case year > MaxYear:
+		return 10000*MaxYear + 1231
+	default:
+		return 10000*year + 100*month + day
 	}
 }
 
@@ -63,12 +135,12 @@ func StringToYMD(str string) (int, error) {
 	}
 
 	if len(str) != 8 || !isInt(str) {
-		return 0, fmt.Errorf("expect string of format YYYYMMDD")
+		return 0, fmt.Errorf("expect string of format YYYYMMDD: %w", ErrBadFormat)
 	}
 
 	yyyymmdd, err := strconv.Atoi(str)
 	if err != nil {
-		return 0, fmt.Errorf("failed to convert string %w", err)
+		return 0, fmt.Errorf("failed to convert string %w: %w", err, ErrBadFormat)
 	}
 
 	if err := ValidateYMD(yyyymmdd); err != nil {
@@ -77,7 +149,22 @@ func StringToYMD(str string) (int, error) {
 	return yyyymmdd, nil
 }
 
-// ValidateYMD returns nil if the passed `yyyymmdd` is of a proper YYYYMMDD form.
+// StringsToYMD returns the integral YYYYMMDD value of each string, as StringToYMD does, so empty strings are 0.
+// If any string is invalid, the error for the first one is returned with its index.
+func StringsToYMD(values []string) ([]int, error) {
+	result := make([]int, len(values))
+	for i, str := range values {
+		yyyymmdd, err := StringToYMD(str)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		result[i] = yyyymmdd
+	}
+	return result, nil
+}
+
+// ValidateYMD returns nil if the passed `yyyymmdd` is of a proper YYYYMMDD form,
+// with a year from MinYear through MaxYear, so 00010101 through 99991231.
 // Zero is a valid value, meaning indeindicating potential auto-detection.
 // Otherwise, returns an error.
 // This function is not forgiving like `time.Date`, e.g. 10/32 (Oct 32) is not considered 11/01 (Nov 1).
@@ -85,20 +172,55 @@ func ValidateYMD(yyyymmdd int) error {
 	if yyyymmdd == 0 {
 		return nil
 	} else if yyyymmdd < 0 {
-		return fmt.Errorf("yyyymmdd is negative")
+		return fmt.Errorf("yyyymmdd is negative: %w", ErrOutOfRange)
 	} else if yyyymmdd > 99999999 {
-		return fmt.Errorf("yyyymmdd is more than 8 digits")
+		return fmt.Errorf("yyyymmdd is more than 8 digits: %w", ErrOutOfRange)
 	}
 	var year int = yyyymmdd / 10000
 	var month int = (yyyymmdd % 10000) / 100
 	var day int = yyyymmdd % 100
-	dt := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
-	if year != dt.Year() || month != int(dt.Month()) || day != dt.Day() {
-		return fmt.Errorf("yyyymmdd is bad or unnormalized")
+	if year < MinYear {
+		return fmt.Errorf("yyyymmdd year 0000 is not supported, years are 0001 through 9999: %w", ErrOutOfRange)
+	}
+	// check directly rather than with time.Date, as this is called for every parsed date
+	if month < 1 || month > 12 || day < 1 || day > daysInMonth(year, month) {
+		return fmt.Errorf("yyyymmdd is bad or unnormalized: %w", ErrOutOfRange)
 	}
 	return nil
 }
 
+// monthDays is the number of days in each month, indexed from 1, in a non-leap year.
+var monthDays = [13]int{0, 31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// daysInMonth returns the number of days in the given month, from 1 through 12, of the Gregorian year.
+func daysInMonth(year, month int) int {
+	if month == 2 && IsLeapYear(year) {
+		return 29
+	}
+	return monthDays[month]
+}
+
+// ValidateYMDStrict returns nil if the passed `yyyymmdd` is of a proper YYYYMMDD form, as ValidateYMD does,
+// but unlike ValidateYMD also returns an error for zero.
+// This allows requiring that a date was explicitly supplied, rather than defaulting to today.
+func ValidateYMDStrict(yyyymmdd int) error {
+	if yyyymmdd == 0 {
+		return fmt.Errorf("yyyymmdd is zero: %w", ErrOutOfRange)
+	}
+	return ValidateYMD(yyyymmdd)
+}
+
+// NormalizeYMD returns the passed `yyyymmdd` normalized the way `time.Date` does in the specified location,
+// e.g. 20231032 (Oct 32) becomes 20231101 (Nov 1) and 20231300 becomes 20231231.
+// It is the permissive counterpart to ValidateYMD.  A valid `yyyymmdd`, including zero, is returned unchanged.
+// If `loc` is nil, then time.Local is used.
+func NormalizeYMD(yyyymmdd int, loc *time.Location) int {
+	if yyyymmdd == 0 {
+		return 0
+	}
+	return TimeToYMD(YMDToTime(yyyymmdd, loc))
+}
+
 // AsDirPath returns the YMDFlag as `"YYYY/MM/DD"` using given path seperator
 // If the YMDFlag is nil, then an empty string is returned.
 func FormatDirPath(ymd YMDFlag, separator rune) string {
@@ -109,45 +231,94 @@ func FormatDirPath(ymd YMDFlag, separator rune) string {
 	return fmt.Sprintf("%04d%c%02d%c%02d", year, separator, month, separator, day)
 }
 
+// FormatDirPathYMD returns the integral `yyyymmdd` as `"YYYY/MM/DD"` using the given path separator,
+// as FormatDirPath does, without first constructing a YMDFlag.
+// Unlike FormatDirPath, a `yyyymmdd` of 0 renders today in `loc`, or local time if that is nil,
+// so that bulk path generation never produces an empty path.  Today is determined using NowFunc.
+func FormatDirPathYMD(yyyymmdd int, loc *time.Location, separator rune) string {
+	return FormatDirPath(YMDFlag{yyyymmdd: yyyymmdd, loc: loc}.resolved(), separator)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // flag.Value interface
 
-// Type implements pflag.Value.Type.  Returns "YMDFlag".
-func (*YMDFlag) Type() string {
-	return "YMDFlag"
+// Type implements pflag.Value.Type, which pflag shows in generated help text.
+// Returns "YMDFlag" if the location is nil or local time, or else includes the location's name,
+// for example "YMDFlag[America/New_York]", so users can tell which timezone the flag expects.
+func (ymd *YMDFlag) Type() string {
+	if ymd == nil || ymd.loc == nil || ymd.loc == time.Local {
+		return "YMDFlag"
+	}
+	return "YMDFlag[" + ymd.loc.String() + "]"
 }
 
-// String implements the flag.Value interface.
-// If the YMDFlag is nil, then an empty string is returned.
-func (ymd *YMDFlag) String() string {
+// String implements the flag.Value and fmt.Stringer interfaces.
+// It returns the zero-padded `"YYYYMMDD"` form, which Set accepts and reproduces.
+// If the YMDFlag is nil, then an empty string is returned; it is not resolved to today.
+// String has a value receiver, so it never mutates the YMDFlag and is safe for logging.
+func (ymd YMDFlag) String() string {
 	return ymd.AsYMDString()
 }
 
-// Set implements the flag.Value interface.
+// Set implements the flag.Value interface, parsing `value` as Parse does in the YMDFlag's location.
 // The default value of empty string `""` implies it is unset
-// and may be auto-filled by some methods.
+// and may be auto-filled by some methods.  The integral sentinel `"0"` is also accepted as unset,
+// so Set accepts anything String produces and reproduces the same YMDFlag.
+// The canonical form is `YYYYMMDD`, but `YYYY-MM-DD`, `YYYY/MM/DD`, and `YYYY-DDD` are also accepted.
+// If AllowRelativeDates is true, the keywords "today", "yesterday", and "tomorrow" and signed day offsets
+// like "-1" and "+7" are also accepted, and are resolved immediately in the YMDFlag's location.
+// Surrounding whitespace is trimmed, so piped `date +%Y%m%d` output is accepted;
+// use StringToYMD for strict parsing.  Dates are validated as by ValidateYMD rather than normalized,
+// so `"20231032"` returns an error wrapping ErrOutOfRange instead of rolling over to November 1.
+// If `value` is invalid, the YMDFlag is unchanged.
 func (ymd *YMDFlag) Set(value string) error {
-	// convert value to YMD int
-	yyyymmdd, err := StringToYMD(value)
+	return ymd.set(value, AllowRelativeDates)
+}
+
+// set is Set, with relative dates accepted only if `relative` is true.
+func (ymd *YMDFlag) set(value string, relative bool) error {
+	parsed, err := parse(value, ymd.loc, relative)
 	if err != nil {
 		return err
 	}
-	ymd.yyyymmdd = yyyymmdd
+	ymd.yyyymmdd = parsed.yyyymmdd
 	return nil
 }
 
+// Get implements the flag.Getter interface, returning a copy of the YMDFlag itself as a YMDFlag value,
+// which carries its location.  A nil YMDFlag is returned as-is, and is not resolved to today.
+func (ymd YMDFlag) Get() any {
+	return ymd
+}
+
+// ParseArg returns the YMDFlag resulting from passing `arg` to the Set method of a flag in the given location,
+// as happens when a flag package parses a command-line argument.
+// This allows testing of command-line date handling without building a FlagSet.
+// An empty `arg` results in a nil YMDFlag, which resolves to today in `loc` when accessed.
+func ParseArg(arg string, loc *time.Location) (YMDFlag, error) {
+	ymd := YMDFlag{loc: loc}
+	if err := ymd.Set(arg); err != nil {
+		return YMDFlag{}, err
+	}
+	return ymd, nil
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // YMDFlag implementation
 
 // NewYMDFlag creates a new YMDFlag for the given time.Time's date and location.
+// As with TimeToYMD, a year outside MinYear through MaxYear saturates rather than resulting in a nil YMDFlag;
+// use TimeToYMDChecked first to reject such times.
 func NewYMDFlag(t time.Time) YMDFlag {
 	var ymd YMDFlag
 	ymd.yyyymmdd = TimeToYMD(t)
 	return ymd
 }
 
-// NewYMDFlag creates a new YMDFlag for the given integral `YYYYMMDD` value, for example `20230704`.
-// Returns a non-nil error if YMDFlag is malformed.  `0` is a valid value.
+// NewYMDFlagFromInt creates a new YMDFlag in local time for the given integral `YYYYMMDD` value, for example `20230704`.
+// `0` is a valid value, resulting in a nil YMDFlag; negative values return a non-nil error wrapping ErrOutOfRange,
+// as does any other malformed value.  Use SetLocation for another location, or `NewYMDFlagFromString("", loc)`
+// to create a nil YMDFlag in `loc` in one call.
 func NewYMDFlagFromInt(i int) (YMDFlag, error) {
 	if err := ValidateYMD(i); err != nil {
 		return YMDFlag{}, err
@@ -155,27 +326,110 @@ func NewYMDFlagFromInt(i int) (YMDFlag, error) {
 	return YMDFlag{yyyymmdd: i}, nil
 }
 
+// NewYMDFlagFromString creates a new YMDFlag in the given location for the string `s`, as parsed by Parse,
+// so it accepts exactly the inputs that Set does, including relative dates if AllowRelativeDates is true.
+// An empty string results in a nil YMDFlag, which resolves to today in `loc` when accessed.
+// Returns a non-nil error if the string is malformed.  Use StringToYMD for strict `YYYYMMDD` parsing.
+func NewYMDFlagFromString(s string, loc *time.Location) (YMDFlag, error) {
+	return Parse(s, loc)
+}
+
+// NewYMDFlagWithDefault creates a new nil YMDFlag in the given location which resolves to the integral
+// `YYYYMMDD` date `def` wherever it would otherwise resolve to today, such as a fixed date in tests.
+// Setting the YMDFlag replaces the date, but the default remains for when it is set back to nil.
+// Relative dates like "yesterday", and methods such as IsToday, still use the current date.
+// A `def` of 0 resolves to today as usual.  Returns a non-nil error if `def` is malformed.
+// The default is not encoded by any of the marshaling methods.
+func NewYMDFlagWithDefault(def int, loc *time.Location) (YMDFlag, error) {
+	if err := ValidateYMD(def); err != nil {
+		return YMDFlag{}, err
+	}
+	return YMDFlag{loc: loc, def: def}, nil
+}
+
+// NewYMDFlagToday creates a new YMDFlag for the current date in the given location, or local time if that is nil.
+// Unlike a nil YMDFlag, the date is fixed when it is created.  Today is determined using NowFunc.
+func NewYMDFlagToday(loc *time.Location) YMDFlag {
+	ymd := YMDFlag{loc: loc}
+	ymd.SetToToday()
+	return ymd
+}
+
+// Clone returns an independent copy of the YMDFlag, including its date and location.
+// The *time.Location is shared rather than copied, which is safe because locations are immutable.
+func (ymd YMDFlag) Clone() YMDFlag {
+	return ymd
+}
+
 // GetYMD returns the YMDFlag as integer `YYYYMMDD`.  It may be zero.
 func (ymd YMDFlag) GetYMD() int {
 	return ymd.yyyymmdd
 }
 
+// Location returns the location of the YMDFlag.  A nil location means local time.
+func (ymd YMDFlag) Location() *time.Location {
+	return ymd.loc
+}
+
+// CanonicalLocation returns the location of the YMDFlag, or `time.Local` if it is nil.
+// Unlike Location, equivalent nil and `time.Local` locations compare equal.
+func (ymd YMDFlag) CanonicalLocation() *time.Location {
+	if ymd.loc == nil {
+		return time.Local
+	}
+	return ymd.loc
+}
+
+// SetLocation sets the location of the YMDFlag.  A nil location means local time.
+// The `yyyymmdd` value is not changed.
+func (ymd *YMDFlag) SetLocation(loc *time.Location) {
+	ymd.loc = loc
+}
+
+// ToUTC returns a copy of the YMDFlag with the same calendar date, but with its location set to `time.UTC`.
+// A nil YMDFlag remains nil, resolving to today in UTC when accessed.
+func (ymd YMDFlag) ToUTC() YMDFlag {
+	ymd.loc = time.UTC
+	return ymd
+}
+
+// UTC returns a copy of the YMDFlag with its location set to `time.UTC`, which is the same as ToUTC.
+// It allows chaining such as `ymd.UTC().AsTime()`.
+func (ymd YMDFlag) UTC() YMDFlag {
+	return ymd.ToUTC()
+}
+
+// Local returns a copy of the YMDFlag with the same calendar date, but with its location set to `time.Local`.
+// A nil YMDFlag remains nil, resolving to today in local time when accessed.
+func (ymd YMDFlag) Local() YMDFlag {
+	ymd.loc = time.Local
+	return ymd
+}
+
 // IsZero returns true if the YMDFlag is nil.  The location is ignored in this case.
 func (ymd YMDFlag) IsZero() bool {
 	return (ymd.yyyymmdd == 0)
 }
 
+// IsUnset returns true if the YMDFlag is nil, meaning no date was supplied, as IsZero does.
+// The location and any default date are ignored, and it is not resolved to today.
+func (ymd YMDFlag) IsUnset() bool {
+	return ymd.IsZero()
+}
+
 // AsYMD returns the YMDFlag as integer `YYYYMMDD`.  Returns 0 if the YMDFlag is nil.
 func (ymd YMDFlag) AsYMD() int {
 	return ymd.yyyymmdd
 }
 
-// AsYMDString returns the YMDFlag as string `"YYYYMMDD"`.  If the YMDFlag is nil, it returns the empty string.
+// AsYMDString returns the YMDFlag as string `"YYYYMMDD"`, always 8 characters with leading zeros,
+// so year 99 is `"00990102"`.  If the YMDFlag is nil, it returns the empty string.
 func (ymd YMDFlag) AsYMDString() string {
 	if ymd.yyyymmdd == 0 {
 		return ""
 	}
-	return strconv.Itoa(ymd.yyyymmdd)
+	var buf [8]byte
+	return string(ymd.AppendYMD(buf[:0]))
 }
 
 // AsYearMonthDay returns the YMDFlag decomposed into Year, Month, and Day.
@@ -190,32 +444,97 @@ func (ymd YMDFlag) AsYearMonthDay() (int, int, int) {
 	return year, month, day
 }
 
+// AsYearMonthDayStrings returns the YMDFlag's year, month, and day as zero-padded strings,
+// such as "2023", "07", and "04", for building custom paths and filenames.
+// Unlike AsYearMonthDay, a nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsYearMonthDayStrings() (year, month, day string) {
+	s := ymd.resolved().AsYMDString()
+	return s[:4], s[4:6], s[6:]
+}
+
 // UpdateNilToNow updates a nil YMDFlag (with `yyyymmdd` == 0) to the current date in the specified location.
-// If location is nil, local time is used.
+// If location is nil, the YMDFlag's location is used, or local time if that is also nil.
+// If the YMDFlag has a default date from NewYMDFlagWithDefault, that is used instead of the current date.
 // If `yyyymmdd` is not nil, then this method does nothing.
 func (ymd *YMDFlag) UpdateNilToNow(location *time.Location) {
 	if ymd.yyyymmdd != 0 {
 		return
 	}
-	now := time.Now()
-	if location != nil {
-		now = now.In(location)
+	if ymd.def != 0 {
+		ymd.yyyymmdd = ymd.def
+		return
 	}
-	ymd.yyyymmdd = TimeToYMD(now)
+	if location == nil {
+		location = ymd.CanonicalLocation()
+	}
+	ymd.yyyymmdd = TimeToYMD(NowFunc().In(location))
 }
 
-// AsTime returns the YMDFlag as a `time.Time“ in local time.  Use `AsTimeWithLoc` to specify a location.
-// If the YMDFlag's `yyyymmdd` is 0, then the YMDFlag is updated with the current date in the Local timezone.
+// Resolve fixes a nil YMDFlag to the current date in its location, or local time if that is nil,
+// or to its default date from NewYMDFlagWithDefault.
+// A YMDFlag with a date is unchanged.  Call it once before sharing a YMDFlag across goroutines,
+// after which even the mutating accessors such as AsTime only read it.
+// It is the same as `UpdateNilToNow(nil)`.  Today is determined using NowFunc.
+func (ymd *YMDFlag) Resolve() {
+	ymd.UpdateNilToNow(nil)
+}
+
+// SetToToday sets the YMDFlag to the current date in its location, or local time if that is nil,
+// replacing any date it already has.  Today is determined using NowFunc.
+func (ymd *YMDFlag) SetToToday() {
+	ymd.yyyymmdd = ymd.today()
+}
+
+// AsTime returns the YMDFlag as a `time.Time“ in its location, or local time if that is nil.
+// Use `AsTimeWithLoc` to specify a different location.
+// If the YMDFlag's `yyyymmdd` is 0, then the YMDFlag is updated with the current date in that location.
+// Where a DST transition skips midnight, the result is normalized by `time.Date` to an instant whose wall clock
+// is not midnight, and may even be on the previous day, and where one repeats midnight, it is one of the two instants.
+// Use `AsTimeSafe` to detect these.
 func (ymd *YMDFlag) AsTime() time.Time {
 	return ymd.AsTimeWithLoc(nil)
 }
 
+// AsTimeStrict returns the YMDFlag as a `time.Time` in its location, or local time if that is nil, as AsTime does.
+// Unlike AsTime, it returns a non-nil error rather than resolving a nil YMDFlag to today,
+// for callers requiring that a date was explicitly supplied.  The receiver is never mutated.
+func (ymd YMDFlag) AsTimeStrict() (time.Time, error) {
+	if _, err := ymd.AsYMDStrict(); err != nil {
+		return time.Time{}, err
+	}
+	return ymd.AsTime(), nil
+}
+
+// AsYMDStrict returns the YMDFlag as integer `YYYYMMDD`, or a non-nil error if the YMDFlag is nil.
+// The error wraps ErrOutOfRange, as from ValidateYMDStrict.  It does not resolve to today.
+func (ymd YMDFlag) AsYMDStrict() (int, error) {
+	if err := ValidateYMDStrict(ymd.yyyymmdd); err != nil {
+		return 0, err
+	}
+	return ymd.yyyymmdd, nil
+}
+
+// AsStartOfDayTime returns midnight at the start of the YMDFlag's date in its location, or local time if that is nil.
+// This is the same as AsTime, except that a nil YMDFlag is resolved to today without mutating the receiver.
+func (ymd YMDFlag) AsStartOfDayTime() time.Time {
+	return ymd.AsTime()
+}
+
+// AsEndOfDayTime returns the last nanosecond, 23:59:59.999999999, of the YMDFlag's date in its location,
+// or local time if that is nil.  It is the inclusive upper bound for closed intervals of times within the date;
+// for half-open intervals, prefer the AsStartOfDayTime of the following day.
+// A nil YMDFlag is resolved to today, without mutating the receiver.
+func (ymd YMDFlag) AsEndOfDayTime() time.Time {
+	year, month, day := ymd.resolved().AsYearMonthDay()
+	return time.Date(year, time.Month(month), day, 23, 59, 59, 999999999, ymd.CanonicalLocation())
+}
+
 // AsTimeWithLoc returns the YMDFlag as a `time.Time` in the specified location.
 // If the YMDFlag's `yyyymmdd` is 0, then the YMDFlag is updated with the current date in the specified location.
-// If `location“ is nil, then `time.Local` is used.
+// If `location“ is nil, then the YMDFlag's location is used, or `time.Local` if that is also nil.
 func (ymd *YMDFlag) AsTimeWithLoc(location *time.Location) time.Time {
 	if location == nil {
-		location = time.Local
+		location = ymd.CanonicalLocation()
 	}
 	ymd.UpdateNilToNow(location)
 	return YMDToTime(ymd.yyyymmdd, location)
@@ -223,16 +542,28 @@ func (ymd *YMDFlag) AsTimeWithLoc(location *time.Location) time.Time {
 
 // AsTimeRawWithLoc returns the YMDFlag as a `time.Time` in the specified location.
 // If the YMDFlag's `yyyymmdd` is 0, then a zero time in that location is returned. No auto-update is performed.
-// If `location“ is nil, then `time.Local` is used.
+// If `location“ is nil, then the YMDFlag's location is used, or `time.Local` if that is also nil.
 func (ymd *YMDFlag) AsTimeRawWithLoc(location *time.Location) time.Time {
 	if location == nil {
-		location = time.Local
+		location = ymd.CanonicalLocation()
 	}
 	return YMDToTime(ymd.yyyymmdd, location)
 }
 
 //////////////////////////////////////////////////////////////////////////////
 
+// today returns the integral `yyyymmdd` of today in the YMDFlag's location, ignoring any default date.
+func (ymd YMDFlag) today() int {
+	return TimeToYMD(NowFunc().In(ymd.CanonicalLocation()))
+}
+
+// resolved returns a copy of the YMDFlag with a nil value resolved to today, or its default date,
+// leaving the receiver untouched.
+func (ymd YMDFlag) resolved() YMDFlag {
+	ymd.UpdateNilToNow(nil)
+	return ymd
+}
+
 // isInt checks if a string can be converted safely to an int
 func isInt(value string) bool {
 	for _, c := range value {
@@ -3,6 +3,10 @@ package ymdflag
 // Copyright (c) 2023 Neomantra BV
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -67,6 +71,263 @@ func TestValidateYMD(t *testing.T) {
 	assert.Error(t, err, "negative date")
 }
 
+// validateYMDWithTime is the original ValidateYMD, which checks normalization using time.Date.
+func validateYMDWithTime(yyyymmdd int) error {
+	if yyyymmdd == 0 {
+		return nil
+	} else if yyyymmdd < 10000*MinYear || yyyymmdd > 99999999 {
+		return ErrOutOfRange
+	}
+	year, month, day := yyyymmdd/10000, (yyyymmdd%10000)/100, yyyymmdd%100
+	dt := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+	if year != dt.Year() || month != int(dt.Month()) || day != dt.Day() {
+		return ErrOutOfRange
+	}
+	return nil
+}
+
+func TestValidateYMDMatchesTime(t *testing.T) {
+	years := []int{0, 1, 4, 100, 400, 1600, 1700, 1899, 1900, 2000, 2023, 2024, 2100, 9999}
+	for _, year := range years {
+		for month := 0; month <= 13; month++ {
+			for day := 0; day <= 32; day++ {
+				yyyymmdd := 10000*year + 100*month + day
+				assert.Equal(t, validateYMDWithTime(yyyymmdd) == nil, ValidateYMD(yyyymmdd) == nil, "%d", yyyymmdd)
+			}
+		}
+	}
+	// month and day fields up to 99
+	for yyyymmdd := 20230000; yyyymmdd <= 20239999; yyyymmdd++ {
+		assert.Equal(t, validateYMDWithTime(yyyymmdd) == nil, ValidateYMD(yyyymmdd) == nil, "%d", yyyymmdd)
+	}
+}
+
+func BenchmarkValidateYMD(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if err := ValidateYMD(20240229); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateYMDWithTime(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if err := validateYMDWithTime(20240229); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// timeToYMDWithFields is the original TimeToYMD, which computes the date separately for each field.
+func timeToYMDWithFields(t time.Time) int {
+	if t.IsZero() || t.Year() < MinYear || t.Year() > MaxYear {
+		return 0
+	}
+	return 10000*t.Year() + 100*int(t.Month()) + t.Day()
+}
+
+func TestYMDFromTime(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	year, month, day := YMDFromTime(time.Date(2023, time.July, 4, 23, 0, 0, 0, time.UTC).In(tokyo))
+	assert.Equal(t, []int{2023, 7, 5}, []int{year, month, day}, "in the Time's location")
+	year, month, day = YMDFromTime(time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []int{10000, 1, 1}, []int{year, month, day}, "out of range years are returned as-is")
+
+	times := []time.Time{{}, time.Unix(0, 0).UTC(), time.Date(1, time.January, 1, 9, 0, 0, 0, tokyo),
+		time.Date(9999, time.December, 31, 23, 0, 0, 0, time.UTC)}
+	for tm := time.Date(1899, time.December, 25, 13, 0, 0, 0, tokyo); tm.Year() < 2101; tm = tm.Add(97 * time.Hour) {
+		times = append(times, tm)
+	}
+	for _, tm := range times {
+		assert.Equal(t, timeToYMDWithFields(tm), TimeToYMD(tm), "%v", tm)
+	}
+}
+
+func BenchmarkTimeToYMD(b *testing.B) {
+	tm := time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < b.N; i++ {
+		if TimeToYMD(tm) != 20240229 {
+			b.Fatal("mismatch")
+		}
+	}
+}
+
+func BenchmarkTimeToYMDWithFields(b *testing.B) {
+	tm := time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < b.N; i++ {
+		if timeToYMDWithFields(tm) != 20240229 {
+			b.Fatal("mismatch")
+		}
+	}
+}
+
+func BenchmarkYMDToTime(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if YMDToTime(20240229, time.UTC).IsZero() {
+			b.Fatal("zero")
+		}
+	}
+}
+
+func TestValidateYMDStrict(t *testing.T) {
+	err := ValidateYMDStrict(20220101)
+	assert.NoError(t, err, "valid date should not return an error")
+
+	err = ValidateYMDStrict(0)
+	assert.Error(t, err, "zero is not ok")
+	assert.NoError(t, ValidateYMD(0), "zero is still ok for ValidateYMD")
+
+	err = ValidateYMDStrict(20240229)
+	assert.NoError(t, err, "leap day is ok")
+
+	for _, yyyymmdd := range []int{20230229, 209901231, 010101, 20221301, 20221241, -1} {
+		assert.Error(t, ValidateYMDStrict(yyyymmdd), "%d", yyyymmdd)
+	}
+}
+
+func TestYearRange(t *testing.T) {
+	assert.NoError(t, ValidateYMD(10101), "00010101 is the earliest date")
+	assert.NoError(t, ValidateYMD(99991231), "99991231 is the latest date")
+	err := ValidateYMD(1231)
+	assert.ErrorIs(t, err, ErrOutOfRange, "year 0000")
+	assert.ErrorContains(t, err, "0001 through 9999")
+	assert.ErrorIs(t, ValidateYMD(101), ErrOutOfRange)
+	assert.ErrorIs(t, ValidateYMD(100000101), ErrOutOfRange, "year 10000")
+
+	_, err = StringToYMD("00001231")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	yyyymmdd, err := StringToYMD("00010101")
+	assert.NoError(t, err)
+	assert.Equal(t, 10101, yyyymmdd)
+	assert.ErrorIs(t, ValidateYM(12), ErrOutOfRange, "year 0000")
+	assert.NoError(t, ValidateYM(101))
+
+	// the extremes convert to and from time.Time
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	for _, yyyymmdd := range []int{10101, 10102, 99991231} {
+		assert.Equal(t, yyyymmdd, TimeToYMD(YMDToTime(yyyymmdd, tokyo)), "%d", yyyymmdd)
+	}
+	assert.Equal(t, time.Date(1, time.January, 1, 0, 0, 0, 0, tokyo), YMDToTime(10101, tokyo))
+	assert.Equal(t, time.Date(9999, time.December, 31, 0, 0, 0, 0, tokyo), YMDToTime(99991231, tokyo))
+
+	// years outside the range saturate rather than becoming the nil sentinel
+	assert.Equal(t, 10101, TimeToYMD(time.Date(0, time.December, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 99991231, TimeToYMD(time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 10101, TimeToYMD(time.Date(-1, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, TimeToYMD(time.Time{}), "the zero time")
+	assert.False(t, NewYMDFlag(time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsUnset(), "not today")
+
+	for _, tm := range []time.Time{time.Date(0, time.December, 31, 0, 0, 0, 0, time.UTC), time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)} {
+		_, err = TimeToYMDChecked(tm)
+		assert.ErrorIs(t, err, ErrOutOfRange, "%v", tm)
+	}
+	yyyymmdd, err = TimeToYMDChecked(time.Date(9999, time.December, 31, 23, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 99991231, yyyymmdd)
+	yyyymmdd, err = TimeToYMDChecked(time.Time{})
+	assert.NoError(t, err, "the zero time")
+	assert.Equal(t, 0, yyyymmdd)
+	var scanned YMDFlag
+	assert.ErrorIs(t, scanned.Scan(time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)), ErrOutOfRange)
+
+	// date arithmetic saturates at the extremes
+	assert.Equal(t, 99991231, mustYMD(t, 99991231).AddDays(1).GetYMD())
+	assert.Equal(t, "99991231", mustYMD(t, 99991231).NextDay().AsYMDString())
+	assert.Equal(t, 99991231, mustYMD(t, 99991130).AddMonths(2).GetYMD())
+	assert.Equal(t, 10101, mustYMD(t, 10101).PrevDay().GetYMD())
+	assert.Equal(t, 10101, mustYMD(t, 20230704).AddYears(-3000).GetYMD())
+	assert.NoError(t, ValidateYMD(mustYMD(t, 20230704).AddYears(9000).GetYMD()))
+
+	// AppendYMD does not truncate values that are not 8 digits
+	assert.Equal(t, []byte("x"), YMDFlag{yyyymmdd: 100000101}.AppendYMD([]byte("x")))
+	assert.Equal(t, []byte("x"), YMDFlag{yyyymmdd: -1}.AppendYMD([]byte("x")))
+	assert.Equal(t, "", YMDFlag{yyyymmdd: 100000101}.AsYMDString())
+
+	// date arithmetic reaches 00010101, even though it is the zero time in UTC
+	assert.Equal(t, 10101, mustYMD(t, 10102).PrevDay().GetYMD())
+	assert.Equal(t, 10101, mustYMD(t, 10201).AddMonths(-1).GetYMD())
+	assert.Equal(t, 10131, mustYMD(t, 10115).MonthEnd().GetYMD())
+
+	_, err = ParseJulian(1, tokyo)
+	assert.ErrorIs(t, err, ErrOutOfRange, "year 0000")
+}
+
+func TestSetRejectsUnnormalized(t *testing.T) {
+	unnormalized := map[string]int{
+		"20231032":   20231032,
+		"20230229":   20230229,
+		"20230431":   20230431,
+		"20231300":   20231300,
+		"2023-10-32": 20231032,
+		"2023/04/31": 20230431,
+	}
+	for value, yyyymmdd := range unnormalized {
+		ymdFlag := mustYMD(t, 20230704)
+		assert.ErrorIs(t, ymdFlag.Set(value), ErrOutOfRange, "%q", value)
+		assert.Equal(t, 20230704, ymdFlag.GetYMD(), "%q leaves the YMDFlag unchanged", value)
+		assert.ErrorIs(t, ValidateYMD(yyyymmdd), ErrOutOfRange, "%q is consistent with ValidateYMD", value)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	badFormat := []string{"2023074", "202307045", "2023o704", "2023-07/04", "July 4", "+1d"}
+	for _, value := range badFormat {
+		var ymdFlag YMDFlag
+		err := ymdFlag.Set(value)
+		assert.True(t, errors.Is(err, ErrBadFormat), "%q: %v", value, err)
+		assert.False(t, errors.Is(err, ErrOutOfRange), "%q: %v", value, err)
+	}
+	_, err := StringToYMD("2023-07-04")
+	assert.True(t, errors.Is(err, ErrBadFormat), "StringToYMD is strict")
+
+	outOfRange := []string{"20230230", "20231301", "20230700", "2023-02-29", "+99999999"}
+	for _, value := range outOfRange {
+		var ymdFlag YMDFlag
+		err := ymdFlag.Set(value)
+		assert.True(t, errors.Is(err, ErrOutOfRange), "%q: %v", value, err)
+		assert.False(t, errors.Is(err, ErrBadFormat), "%q: %v", value, err)
+	}
+	assert.ErrorContains(t, (&YMDFlag{}).Set("20230230"), "unnormalized", "messages are kept")
+
+	for _, yyyymmdd := range []int{-1, 123456789, 20230230} {
+		assert.True(t, errors.Is(ValidateYMD(yyyymmdd), ErrOutOfRange), "%d", yyyymmdd)
+		_, err := NewYMDFlagFromInt(yyyymmdd)
+		assert.True(t, errors.Is(err, ErrOutOfRange), "%d", yyyymmdd)
+	}
+	assert.True(t, errors.Is(ValidateYMDStrict(0), ErrOutOfRange), "zero is strictly out of range")
+	assert.True(t, errors.Is((&YMDFlag{}).Scan(int64(-1)), ErrOutOfRange))
+}
+
+func TestNormalizeYMD(t *testing.T) {
+	assert.Equal(t, 20231101, NormalizeYMD(20231032, nil), "day overflow")
+	assert.Equal(t, 20230301, NormalizeYMD(20230229, time.UTC), "leap day on wrong year")
+	assert.Equal(t, 20240101, NormalizeYMD(20231301, time.UTC), "month overflow")
+	assert.Equal(t, 20240303, NormalizeYMD(20231432, time.UTC), "month and day overflow")
+	assert.Equal(t, 20230630, NormalizeYMD(20230700, time.UTC), "day zero is the previous month's last day")
+	assert.Equal(t, 20231231, NormalizeYMD(20231300, time.UTC), "month overflow with day zero")
+
+	assert.Equal(t, 20230704, NormalizeYMD(20230704, time.UTC), "valid date is unchanged")
+	assert.Equal(t, 0, NormalizeYMD(0, time.UTC), "zero is unchanged")
+	for _, yyyymmdd := range []int{20231032, 20231301, 20230229} {
+		assert.NoError(t, ValidateYMD(NormalizeYMD(yyyymmdd, nil)), "normalized %d is valid", yyyymmdd)
+	}
+}
+
+func TestFormatDirPathYMD(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	for _, yyyymmdd := range []int{20230704, 20231231, 10101, 990102} {
+		expected := FormatDirPath(mustYMDIn(t, yyyymmdd, tokyo), '/')
+		assert.Equal(t, expected, FormatDirPathYMD(yyyymmdd, tokyo, '/'), "%d", yyyymmdd)
+	}
+	assert.Equal(t, `2023\07\04`, FormatDirPathYMD(20230704, nil, '\\'))
+
+	// 2023-07-04 22:00 UTC is already Jul 5 in Tokyo
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2023/07/05", FormatDirPathYMD(0, tokyo, '/'), "zero is today in loc")
+	assert.Equal(t, FormatDirPath(NewYMDFlagToday(time.UTC), '/'), FormatDirPathYMD(0, time.UTC, '/'))
+	assert.Equal(t, "", FormatDirPath(YMDFlag{}, '/'), "FormatDirPath keeps nil empty")
+}
+
 func TestAsYearMonthDay(t *testing.T) {
 
 	// default is zero
@@ -83,6 +344,21 @@ func TestAsYearMonthDay(t *testing.T) {
 	assert.Equal(t, 2, day)
 }
 
+func TestAsYearMonthDayStrings(t *testing.T) {
+	year, month, day := mustYMD(t, 20230704).AsYearMonthDayStrings()
+	assert.Equal(t, []string{"2023", "07", "04"}, []string{year, month, day}, "single-digit month and day")
+	year, month, day = mustYMD(t, 20231225).AsYearMonthDayStrings()
+	assert.Equal(t, []string{"2023", "12", "25"}, []string{year, month, day})
+	year, month, day = mustYMD(t, 990102).AsYearMonthDayStrings()
+	assert.Equal(t, []string{"0099", "01", "02"}, []string{year, month, day}, "four-digit year")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	year, month, day = zero.AsYearMonthDayStrings()
+	assert.Equal(t, []string{"2023", "07", "04"}, []string{year, month, day}, "nil resolves to today")
+	assert.True(t, zero.IsZero())
+}
+
 func TestNewFlagFromInt(t *testing.T) {
 	flag, err := NewYMDFlagFromInt(0)
 	assert.NoError(t, err, "zero is ok")
@@ -112,3 +388,487 @@ func TestStringToYMD(t *testing.T) {
 	assert.NoError(t, err, "empty string should not return an error")
 	assert.Equal(t, 0, yyyymmdd)
 }
+
+func TestStringsToYMD(t *testing.T) {
+	result, err := StringsToYMD([]string{"20230101", "", "20240229", "20231231"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20230101, 0, 20240229, 20231231}, result, "empty string is 0")
+
+	result, err = StringsToYMD(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	_, err = StringsToYMD([]string{"20230101", "20230102", "20230230", "bad"})
+	assert.ErrorContains(t, err, "index 2: ", "first error")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = StringsToYMD([]string{"2023-01-01"})
+	assert.ErrorContains(t, err, "index 0: ")
+	assert.True(t, errors.Is(err, ErrBadFormat))
+}
+
+func TestNewYMDFlagFromInt(t *testing.T) {
+	ymdFlag, err := NewYMDFlagFromInt(20230704)
+	assert.NoError(t, err)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	assert.Nil(t, ymdFlag.Location(), "local time")
+	assert.False(t, ymdFlag.IsUnset())
+
+	ymdFlag, err = NewYMDFlagFromInt(0)
+	assert.NoError(t, err, "0 is the nil sentinel")
+	assert.True(t, ymdFlag.IsUnset())
+	assert.True(t, ymdFlag.IsZero())
+
+	for _, i := range []int{-1, -20230704} {
+		_, err = NewYMDFlagFromInt(i)
+		assert.ErrorIs(t, err, ErrOutOfRange, "negative %d", i)
+	}
+
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymdFlag.SetLocation(loc)
+	assert.True(t, ymdFlag.IsUnset(), "location does not set the date")
+	assert.Equal(t, loc, ymdFlag.Location())
+}
+
+func TestIsUnset(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	ymdFlag := mustYMDIn(t, 0, time.UTC)
+	assert.True(t, ymdFlag.IsUnset())
+	assert.Equal(t, 20230704, ymdFlag.resolved().GetYMD())
+	assert.True(t, ymdFlag.IsUnset(), "not resolved to today")
+
+	withDefault, err := NewYMDFlagWithDefault(20200101, time.UTC)
+	assert.NoError(t, err)
+	assert.True(t, withDefault.IsUnset(), "default date is ignored")
+	assert.NoError(t, withDefault.Set("20230705"))
+	assert.False(t, withDefault.IsUnset())
+}
+
+func TestNewYMDFlagFromString(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+
+	ymdFlag, err := NewYMDFlagFromString("20220101", loc)
+	assert.NoError(t, err, "valid date should not return an error")
+	assert.Equal(t, 20220101, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location())
+	assert.Equal(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, loc), ymdFlag.AsTime())
+
+	for _, str := range []string{"hello world", "123456789", "20230230", "2023/02/30"} {
+		_, err = NewYMDFlagFromString(str, loc)
+		assert.Error(t, err, "malformed %q", str)
+	}
+
+	// the constructor and Set accept the same inputs
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	for _, str := range []string{"2023/01/05", "2023-01-05", " 20230105 ", "2023-005", "0", "yesterday", "+7", "bad", "20230132"} {
+		fromString, err := NewYMDFlagFromString(str, time.UTC)
+		set := mustYMDIn(t, 0, time.UTC)
+		setErr := set.Set(str)
+		assert.Equal(t, setErr, err, "%q", str)
+		if err == nil {
+			assert.Equal(t, set, fromString, "%q", str)
+		}
+	}
+
+	ymdFlag, err = NewYMDFlagFromString("", loc)
+	assert.NoError(t, err, "empty string should not return an error")
+	assert.True(t, ymdFlag.IsZero())
+	assert.Equal(t, loc, ymdFlag.Location(), "nil flag keeps the location")
+
+	ymdFlag, err = NewYMDFlagFromString("20230704", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ymdFlag.Location())
+}
+
+func TestType(t *testing.T) {
+	var ymdFlag YMDFlag
+	assert.Equal(t, "YMDFlag", ymdFlag.Type(), "nil location")
+	ymdFlag.SetLocation(time.Local)
+	assert.Equal(t, "YMDFlag", ymdFlag.Type(), "local time")
+	ymdFlag.SetLocation(mustLoadLocation(t, "America/New_York"))
+	assert.Equal(t, "YMDFlag[America/New_York]", ymdFlag.Type())
+	ymdFlag.SetLocation(time.FixedZone("UTC+14", 14*60*60))
+	assert.Equal(t, "YMDFlag[UTC+14]", ymdFlag.Type())
+	assert.Equal(t, "YMDFlag", (*YMDFlag)(nil).Type())
+}
+
+func TestLocation(t *testing.T) {
+	var ymdFlag YMDFlag
+	assert.Nil(t, ymdFlag.Location(), "default location is nil")
+
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymdFlag = mustYMD(t, 20230704)
+	ymdFlag.SetLocation(loc)
+	assert.Equal(t, loc, ymdFlag.Location())
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "setting location does not change the date")
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, loc), ymdFlag.AsTime())
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), ymdFlag.AsTimeWithLoc(time.UTC))
+}
+
+func TestCanonicalLocation(t *testing.T) {
+	nilLoc, local := mustYMD(t, 20230704), mustYMDIn(t, 20230704, time.Local)
+	assert.NotEqual(t, nilLoc.Location(), local.Location())
+	assert.Equal(t, time.Local, nilLoc.CanonicalLocation())
+	assert.Equal(t, local.CanonicalLocation(), nilLoc.CanonicalLocation())
+	assert.Equal(t, local.AsTime(), nilLoc.AsTime())
+	assert.Equal(t, local.AsTimeRawWithLoc(nil), nilLoc.AsTimeRawWithLoc(nil))
+
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	assert.Equal(t, loc, mustYMDIn(t, 20230704, loc).CanonicalLocation())
+}
+
+func TestParseArg(t *testing.T) {
+	loc := time.FixedZone("UTC-12", -12*60*60)
+
+	ymdFlag, err := ParseArg("20230704", loc)
+	assert.NoError(t, err)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+	assert.Equal(t, loc, ymdFlag.Location())
+
+	_, err = ParseArg("2023074", loc)
+	assert.Error(t, err, "too few digits")
+
+	_, err = ParseArg("20230732", loc)
+	assert.Error(t, err, "invalid day")
+
+	ymdFlag, err = ParseArg("", loc)
+	assert.NoError(t, err, "empty arg is allowed")
+	assert.True(t, ymdFlag.IsZero(), "empty arg is unset")
+	expected := time.Now().In(loc)
+	result := ymdFlag.AsTime()
+	assert.Equal(t, expected.Year(), result.Year())
+	assert.Equal(t, expected.Month(), result.Month())
+	assert.Equal(t, expected.Day(), result.Day())
+	assert.Equal(t, loc, result.Location(), "today is resolved in the flag's location")
+}
+
+func TestToUTC(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymdFlag := mustYMD(t, 20230704)
+	ymdFlag.SetLocation(loc)
+
+	utcFlag := ymdFlag.ToUTC()
+	assert.Equal(t, 20230704, utcFlag.GetYMD(), "calendar date is preserved")
+	assert.Equal(t, time.UTC, utcFlag.Location())
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), utcFlag.AsTime())
+	assert.Equal(t, loc, ymdFlag.Location(), "original is unchanged")
+}
+
+func TestUTCLocal(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymdFlag := mustYMDIn(t, 20230704, loc)
+
+	utcFlag := ymdFlag.UTC()
+	assert.Equal(t, time.UTC, utcFlag.Location())
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), utcFlag.AsTime())
+
+	localFlag := ymdFlag.Local()
+	assert.Equal(t, time.Local, localFlag.Location())
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.Local), localFlag.AsTime())
+	assert.Equal(t, loc, ymdFlag.Location(), "original is unchanged")
+
+	assert.Equal(t, "2023-07-04T00:00:00Z", ymdFlag.UTC().AsFormat(time.RFC3339), "chainable")
+	assert.True(t, YMDFlag{}.Local().IsZero(), "nil remains nil")
+}
+
+func TestClone(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	original := mustYMDIn(t, 20230704, loc)
+	clone := original.Clone()
+	assert.Equal(t, original, clone)
+
+	clone = clone.AddDays(1)
+	clone.SetLocation(time.UTC)
+	assert.Equal(t, 20230705, clone.GetYMD())
+	assert.Equal(t, 20230704, original.GetYMD(), "original is unchanged")
+	assert.Equal(t, loc, original.Location(), "original is unchanged")
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	zeroClone := zero.Clone()
+	zeroClone.UpdateNilToNow(nil)
+	assert.Equal(t, 20230704, zeroClone.GetYMD())
+	assert.True(t, zero.IsZero(), "original is unchanged")
+}
+
+func TestAsStartEndOfDayTime(t *testing.T) {
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	ymdFlag := mustYMDIn(t, 20230704, loc)
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, loc), ymdFlag.AsStartOfDayTime())
+	assert.Equal(t, ymdFlag.AsTime(), ymdFlag.AsStartOfDayTime())
+
+	end := ymdFlag.AsEndOfDayTime()
+	assert.Equal(t, time.Date(2023, time.July, 4, 23, 59, 59, 999999999, loc), end)
+	assert.Equal(t, 999999999, end.Nanosecond())
+	assert.Equal(t, loc, end.Location())
+	assert.Equal(t, time.Date(2023, time.July, 5, 0, 0, 0, 0, loc), end.Add(time.Nanosecond), "next is the following midnight")
+	assert.Equal(t, time.Local, mustYMD(t, 20230704).AsEndOfDayTime().Location(), "nil location is local time")
+
+	// the day DST ends in New York is 25 hours long
+	newYork := mustLoadLocation(t, "America/New_York")
+	dstEnd := mustYMDIn(t, 20231105, newYork)
+	assert.Equal(t, 25*time.Hour-time.Nanosecond, dstEnd.AsEndOfDayTime().Sub(dstEnd.AsStartOfDayTime()))
+
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	zero := mustYMDIn(t, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), zero.AsStartOfDayTime())
+	assert.Equal(t, time.Date(2023, time.July, 4, 23, 59, 59, 999999999, time.UTC), zero.AsEndOfDayTime())
+	assert.True(t, zero.IsZero(), "not mutated")
+}
+
+// setNow pins NowFunc to the given time for the duration of the test.
+func setNow(t *testing.T, now time.Time) {
+	t.Helper()
+	saved := NowFunc
+	NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { NowFunc = saved })
+}
+
+func TestResolve(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+
+	ymdFlag := mustYMDIn(t, 0, tokyo)
+	ymdFlag.Resolve()
+	assert.Equal(t, 20230705, ymdFlag.GetYMD(), "today in its location")
+	assert.Equal(t, tokyo, ymdFlag.Location())
+
+	ymdFlag = mustYMDIn(t, 20220101, tokyo)
+	ymdFlag.Resolve()
+	assert.Equal(t, 20220101, ymdFlag.GetYMD(), "a date is unchanged")
+}
+
+// TestConcurrentAccess shares a nil YMDFlag across goroutines, and is meaningful under `go test -race`.
+func TestConcurrentAccess(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	shared := mustYMDIn(t, 0, time.UTC)
+	midnight := time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// value-receiver methods resolve a copy, so they never write the shared YMDFlag
+			assert.Equal(t, midnight, shared.AsStartOfDayTime())
+			assert.Equal(t, "2023-07-04", shared.AsISOString())
+			assert.Equal(t, 20230705, shared.NextDay().GetYMD())
+			assert.True(t, shared.IsToday())
+			assert.Equal(t, "", shared.String())
+		}()
+	}
+	wg.Wait()
+	assert.True(t, shared.IsZero())
+
+	shared.Resolve()
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// once resolved, the mutating accessors only read
+			assert.Equal(t, midnight, shared.AsTime())
+			assert.Equal(t, midnight, shared.AsTimeWithLoc(time.UTC))
+			shared.UpdateNilToNow(nil)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 20230704, shared.GetYMD())
+}
+
+func TestAsTimeYMDStrict(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+	ymdFlag := mustYMDIn(t, 20230704, loc)
+	tm, err := ymdFlag.AsTimeStrict()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, time.July, 4, 0, 0, 0, 0, loc), tm)
+	yyyymmdd, err := ymdFlag.AsYMDStrict()
+	assert.NoError(t, err)
+	assert.Equal(t, 20230704, yyyymmdd)
+
+	zero := mustYMDIn(t, 0, loc)
+	tm, err = zero.AsTimeStrict()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.True(t, tm.IsZero())
+	yyyymmdd, err = zero.AsYMDStrict()
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.Equal(t, 0, yyyymmdd)
+	assert.True(t, zero.IsZero(), "nil is not resolved to today")
+}
+
+func TestNewYMDFlagWithDefault(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 12, 0, 0, 0, time.UTC))
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+
+	ymdFlag, err := NewYMDFlagWithDefault(20200101, tokyo)
+	assert.NoError(t, err)
+	assert.True(t, ymdFlag.IsZero(), "nil until resolved")
+	assert.Equal(t, tokyo, ymdFlag.Location())
+	assert.Equal(t, "", ymdFlag.String())
+	assert.Equal(t, time.Date(2020, time.January, 1, 0, 0, 0, 0, tokyo), ymdFlag.AsStartOfDayTime(), "the default is used")
+	assert.Equal(t, "2020-01-01", ymdFlag.AsISOString())
+	assert.Equal(t, 20200102, ymdFlag.NextDay().GetYMD())
+	assert.False(t, ymdFlag.IsToday())
+	assert.True(t, ymdFlag.IsPast())
+
+	// setting replaces the date, and setting back to nil restores the default
+	assert.NoError(t, ymdFlag.Set("20230101"))
+	assert.Equal(t, "2023-01-01", ymdFlag.AsISOString())
+	assert.NoError(t, ymdFlag.Set(""))
+	assert.Equal(t, "2020-01-01", ymdFlag.AsISOString())
+	assert.NoError(t, ymdFlag.Set("yesterday"), "relative dates use the current date")
+	assert.Equal(t, 20230703, ymdFlag.GetYMD())
+
+	mutated, err := NewYMDFlagWithDefault(20200101, tokyo)
+	assert.NoError(t, err)
+	mutated.UpdateNilToNow(time.UTC)
+	assert.Equal(t, 20200101, mutated.GetYMD(), "UpdateNilToNow uses the default")
+	mutated, _ = NewYMDFlagWithDefault(20200101, tokyo)
+	mutated.SetToToday()
+	assert.Equal(t, 20230704, mutated.GetYMD(), "SetToToday uses the current date")
+
+	// a plain zero flag, or a zero default, still resolves to today
+	assert.Equal(t, "2023-07-04", mustYMDIn(t, 0, time.UTC).AsISOString())
+	noDefault, err := NewYMDFlagWithDefault(0, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-07-04", noDefault.AsISOString())
+	assert.True(t, noDefault.IsToday())
+
+	_, err = NewYMDFlagWithDefault(20230230, tokyo)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestNowFunc(t *testing.T) {
+	setNow(t, time.Date(2023, time.July, 4, 23, 0, 0, 0, time.UTC))
+
+	var ymdFlag YMDFlag
+	ymdFlag.UpdateNilToNow(time.UTC)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD())
+
+	ymdFlag = YMDFlag{}
+	ymdFlag.UpdateNilToNow(time.FixedZone("UTC+2", 2*60*60))
+	assert.Equal(t, 20230705, ymdFlag.GetYMD(), "today is in the given location")
+}
+
+func TestStringDoesNotMutate(t *testing.T) {
+	var ymdFlag YMDFlag
+	assert.Equal(t, "", ymdFlag.String())
+	assert.Equal(t, "", (&ymdFlag).String())
+	assert.True(t, ymdFlag.IsZero(), "String must not resolve a nil flag to today")
+
+	assert.Equal(t, "20230704", fmt.Sprint(mustYMD(t, 20230704)), "values print via String")
+	assert.Equal(t, "20230704", fmt.Sprintf("%v", &YMDFlag{yyyymmdd: 20230704}), "pointers print via String")
+	_ = fmt.Sprint(ymdFlag)
+	assert.True(t, ymdFlag.IsZero(), "printing must not resolve a nil flag to today")
+}
+
+func TestStringSetRoundTrip(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	flags := []YMDFlag{
+		{},
+		mustYMD(t, 20230704),
+		mustYMD(t, 10101),
+		mustYMD(t, 99991231),
+		mustYMDIn(t, 0, time.UTC),
+		mustYMDIn(t, 20240229, newYork),
+	}
+	for _, f := range flags {
+		// start from a different date in the same location, as when reloading flag state
+		reloaded := mustYMDIn(t, 20000101, f.Location())
+		assert.NoError(t, reloaded.Set(f.String()), "%q", f.String())
+		assert.Equal(t, f, reloaded, "%q", f.String())
+		assert.Equal(t, f.String(), reloaded.String())
+	}
+
+	var ymdFlag YMDFlag
+	assert.NoError(t, ymdFlag.Set("0"), "integral zero is unset")
+	assert.True(t, ymdFlag.IsZero())
+	assert.NoError(t, ymdFlag.Set(" 0\n"))
+	assert.True(t, ymdFlag.IsZero())
+	assert.Error(t, ymdFlag.Set("00"))
+}
+
+func TestPaddedStrings(t *testing.T) {
+	early := mustYMD(t, 990102) // 0099-01-02
+	assert.Equal(t, "00990102", early.AsYMDString())
+	assert.Equal(t, "00990102", early.String())
+	assert.Equal(t, "00990102", fmt.Sprint(early))
+	assert.Equal(t, "00010101", mustYMD(t, 10101).AsYMDString())
+	assert.Len(t, mustYMD(t, 10101).AsYMDString(), 8)
+	assert.Equal(t, "20230704", mustYMD(t, 20230704).AsYMDString())
+	assert.Equal(t, "", YMDFlag{}.AsYMDString())
+
+	text, err := early.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "00990102", string(text))
+	data, err := early.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `"00990102"`, string(data))
+	assert.Equal(t, []string{"00990102"}, (&YMDFlagSlice{early}).GetSlice())
+
+	// the padded form round-trips through Set and StringToYMD
+	var reloaded YMDFlag
+	assert.NoError(t, reloaded.Set(early.String()))
+	assert.Equal(t, early, reloaded)
+	yyyymmdd, err := StringToYMD(early.AsYMDString())
+	assert.NoError(t, err)
+	assert.Equal(t, 990102, yyyymmdd)
+
+	ym, err := NewYMFlagFromInt(9901)
+	assert.NoError(t, err)
+	assert.Equal(t, "009901", ym.String())
+}
+
+func TestGet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var ymdFlag YMDFlag
+	fs.Var(&ymdFlag, "date", "YYYYMMDD date")
+	assert.NoError(t, fs.Parse([]string{"-date", "20230704"}))
+
+	getter, ok := fs.Lookup("date").Value.(flag.Getter)
+	assert.True(t, ok, "implements flag.Getter")
+	value := getter.Get()
+	assert.IsType(t, YMDFlag{}, value)
+	assert.Equal(t, 20230704, value.(YMDFlag).GetYMD())
+
+	assert.Equal(t, YMDFlag{}, YMDFlag{}.Get(), "nil flag is not resolved")
+}
+
+func TestSetTrimsWhitespace(t *testing.T) {
+	for _, value := range []string{"20230704\n", " 20230704", "20230704  ", "\t20230704\r\n"} {
+		var ymdFlag YMDFlag
+		assert.NoError(t, ymdFlag.Set(value), "%q", value)
+		assert.Equal(t, 20230704, ymdFlag.GetYMD(), "%q", value)
+	}
+
+	var ymdFlag YMDFlag
+	assert.NoError(t, ymdFlag.Set(" \n"), "whitespace only is unset")
+	assert.True(t, ymdFlag.IsZero())
+	assert.Error(t, ymdFlag.Set("2023 0704"), "inner whitespace is not trimmed")
+
+	_, err := StringToYMD("20230704\n")
+	assert.Error(t, err, "StringToYMD remains strict")
+}
+
+func TestSetToToday(t *testing.T) {
+	// 2023-07-04 22:00 UTC is already Jul 5 in UTC+3
+	setNow(t, time.Date(2023, time.July, 4, 22, 0, 0, 0, time.UTC))
+	east := time.FixedZone("UTC+3", 3*60*60)
+
+	ymdFlag := mustYMDIn(t, 20220101, time.UTC)
+	ymdFlag.SetToToday()
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "replaces a set date")
+	assert.Equal(t, time.UTC, ymdFlag.Location())
+
+	ymdFlag = mustYMDIn(t, 0, east)
+	ymdFlag.SetToToday()
+	assert.Equal(t, 20230705, ymdFlag.GetYMD(), "today in the flag's location")
+
+	ymdFlag = NewYMDFlagToday(east)
+	assert.Equal(t, 20230705, ymdFlag.GetYMD(), "today in the given location")
+	assert.Equal(t, east, ymdFlag.Location())
+	ymdFlag = NewYMDFlagToday(time.UTC)
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "today in the given location")
+
+	setNow(t, time.Date(2023, time.July, 6, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 20230704, ymdFlag.GetYMD(), "fixed when created")
+	assert.Equal(t, TimeToYMD(NowFunc().Local()), NewYMDFlagToday(nil).GetYMD(), "nil is local time")
+}
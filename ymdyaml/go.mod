@@ -0,0 +1,15 @@
+module github.com/neomantra/ymdflag/ymdyaml
+
+go 1.21
+
+require (
+	github.com/neomantra/ymdflag v0.0.0-20261014091415-ffb5d12bd187
+	github.com/stretchr/testify v1.8.4
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+)
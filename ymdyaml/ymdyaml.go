@@ -0,0 +1,57 @@
+// Package ymdyaml provides YAML support for ymdflag.YMDFlag using gopkg.in/yaml.v3.
+//
+// It is a separate module so that users of ymdflag who do not need YAML do not depend on gopkg.in/yaml.v3.
+package ymdyaml
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/neomantra/ymdflag"
+	"gopkg.in/yaml.v3"
+)
+
+// YMDFlag wraps ymdflag.YMDFlag to implement the yaml.Marshaler and yaml.Unmarshaler interfaces.
+// All ymdflag.YMDFlag methods are promoted, so a *YMDFlag may also be used as a command-line flag.
+type YMDFlag struct {
+	ymdflag.YMDFlag
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The YMDFlag is encoded as a string `"YYYYMMDD"`, or `null` if the YMDFlag is nil.
+// A nil YMDFlag is not resolved to today.  The location is not encoded.
+func (ymd YMDFlag) MarshalYAML() (any, error) {
+	if ymd.IsZero() {
+		return nil, nil
+	}
+	return ymd.AsYMDString(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// It accepts a string scalar or an integer scalar `YYYYMMDD`, both parsed as by UnmarshalText,
+// so relative dates such as `yesterday` are rejected, and an empty string and `0` result in a nil YMDFlag.
+// The YMDFlag's location and any default date from ymdflag.NewYMDFlagWithDefault are preserved.
+// Note that yaml.v3 does not call UnmarshalYAML for `null`, leaving the YMDFlag unchanged.
+func (ymd *YMDFlag) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expect YAML scalar of format YYYYMMDD", value.Line)
+	}
+	text := value.Value
+	if value.Tag == "!!int" {
+		var yyyymmdd int
+		if err := value.Decode(&yyyymmdd); err != nil {
+			return err
+		}
+		if yyyymmdd < 0 {
+			// report the range rather than the format of the signed string
+			return fmt.Errorf("line %d: yyyymmdd is negative: %w", value.Line, ymdflag.ErrOutOfRange)
+		}
+		text = strconv.Itoa(yyyymmdd)
+	}
+	if err := ymd.UnmarshalText([]byte(text)); err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	return nil
+}
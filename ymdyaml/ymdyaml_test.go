@@ -0,0 +1,99 @@
+package ymdyaml
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neomantra/ymdflag"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type config struct {
+	Start YMDFlag `yaml:"start"`
+	End   YMDFlag `yaml:"end"`
+}
+
+func mustYMD(t *testing.T, yyyymmdd int) YMDFlag {
+	t.Helper()
+	ymd, err := ymdflag.NewYMDFlagFromInt(yyyymmdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return YMDFlag{ymd}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	out, err := yaml.Marshal(config{Start: mustYMD(t, 20230704)})
+	assert.NoError(t, err)
+	assert.Equal(t, "start: \"20230704\"\nend: null\n", string(out))
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	var cfg config
+	assert.NoError(t, yaml.Unmarshal([]byte("start: \"20230704\"\nend: 20240229\n"), &cfg))
+	assert.Equal(t, 20230704, cfg.Start.GetYMD(), "string")
+	assert.Equal(t, 20240229, cfg.End.GetYMD(), "integer")
+
+	cfg = config{}
+	assert.NoError(t, yaml.Unmarshal([]byte("start: 2023-07-04\nend: ~\n"), &cfg))
+	assert.Equal(t, 20230704, cfg.Start.GetYMD(), "dashed string")
+	assert.True(t, cfg.End.IsZero(), "null")
+
+	assert.Error(t, yaml.Unmarshal([]byte("start: 20230230\n"), &cfg), "invalid integer")
+	assert.Error(t, yaml.Unmarshal([]byte("start: someday\n"), &cfg), "invalid string")
+	assert.Error(t, yaml.Unmarshal([]byte("start: [20230704]\n"), &cfg), "not a scalar")
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("start: yesterday\n"), &cfg), ymdflag.ErrBadFormat, "relative date")
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("start: \"+7\"\n"), &cfg), ymdflag.ErrBadFormat, "relative offset")
+
+	// yaml.v3 does not call UnmarshalYAML for null, so a date already set is unchanged
+	cfg = config{}
+	assert.NoError(t, cfg.End.Set("20230704"))
+	assert.NoError(t, yaml.Unmarshal([]byte("end: null\n"), &cfg))
+	assert.Equal(t, 20230704, cfg.End.GetYMD(), "null is not decoded")
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	in := config{Start: mustYMD(t, 20230704), End: mustYMD(t, 20231231)}
+	data, err := yaml.Marshal(in)
+	assert.NoError(t, err)
+
+	var out config
+	out.Start.SetLocation(tokyo)
+	out.End.SetLocation(tokyo)
+	assert.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, 20230704, out.Start.GetYMD())
+	assert.Equal(t, 20231231, out.End.GetYMD())
+	assert.Equal(t, tokyo, out.Start.Location(), "preset location is preserved")
+	assert.Equal(t, tokyo, out.End.Location(), "preset location is preserved")
+
+	assert.NoError(t, yaml.Unmarshal([]byte("start: \"\"\nend: 0\n"), &out))
+	assert.True(t, out.Start.IsZero(), "empty string")
+	assert.True(t, out.End.IsZero(), "zero")
+	assert.Equal(t, tokyo, out.Start.Location(), "preset location is preserved")
+	assert.Equal(t, tokyo, out.End.Location(), "preset location is preserved")
+}
+
+func TestUnmarshalYAMLDefault(t *testing.T) {
+	ymd, err := ymdflag.NewYMDFlagWithDefault(20200101, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{"start: 0\n", "start: \"\"\n", "start: \"0\"\n"} {
+		cfg := config{Start: YMDFlag{ymd}}
+		assert.NoError(t, yaml.Unmarshal([]byte("start: 20230704\n"), &cfg))
+		assert.Equal(t, 20230704, cfg.Start.GetYMD())
+		assert.NoError(t, yaml.Unmarshal([]byte(doc), &cfg), doc)
+		assert.True(t, cfg.Start.IsZero(), doc)
+		cfg.Start.Resolve()
+		assert.Equal(t, 20200101, cfg.Start.GetYMD(), "%q keeps the default", doc)
+		assert.Equal(t, time.UTC, cfg.Start.Location(), doc)
+	}
+
+	var cfg config
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("start: -1\n"), &cfg), ymdflag.ErrOutOfRange, "negative integer")
+	assert.ErrorIs(t, yaml.Unmarshal([]byte("start: 20231032\n"), &cfg), ymdflag.ErrOutOfRange, "integers are validated as by Set")
+}
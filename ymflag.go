@@ -0,0 +1,121 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// YMFlag represents a Golang flag.Value for `YYYYMM`-specified months, for data with only monthly precision.
+//
+// It stores the first day of the month as a YMDFlag, sharing its location handling.
+// The special value of 0 indicates that the value is indeterminate, and resolves to the current month.
+// It implements the flag.Value and pflag.Value interfaces.
+type YMFlag struct {
+	ymd YMDFlag // first day of the month, or nil
+}
+
+// ValidateYM returns nil if the passed `yyyymm` is of a proper YYYYMM form, with a year from MinYear through MaxYear
+// and a month from 1 through 12.
+// Zero is a valid value, meaning the current month.  Otherwise, returns an error wrapping ErrOutOfRange.
+func ValidateYM(yyyymm int) error {
+	if yyyymm == 0 {
+		return nil
+	} else if yyyymm < 0 {
+		return fmt.Errorf("yyyymm is negative: %w", ErrOutOfRange)
+	} else if yyyymm > 999999 {
+		return fmt.Errorf("yyyymm is more than 6 digits: %w", ErrOutOfRange)
+	}
+	if yyyymm < 100*MinYear {
+		return fmt.Errorf("yyyymm year 0000 is not supported, years are 0001 through 9999: %w", ErrOutOfRange)
+	}
+	if month := yyyymm % 100; month < 1 || month > 12 {
+		return fmt.Errorf("yyyymm month %d is invalid: %w", month, ErrOutOfRange)
+	}
+	return nil
+}
+
+// NewYMFlagFromInt creates a new YMFlag for the given integral `YYYYMM` value, for example `202307`.
+// Returns a non-nil error if it is malformed.  `0` is a valid value.
+func NewYMFlagFromInt(i int) (YMFlag, error) {
+	if err := ValidateYM(i); err != nil {
+		return YMFlag{}, err
+	}
+	var ym YMFlag
+	if i != 0 {
+		ym.ymd.yyyymmdd = 100*i + 1
+	}
+	return ym, nil
+}
+
+// Type implements pflag.Value.Type.  Returns "YMFlag".
+func (*YMFlag) Type() string {
+	return "YMFlag"
+}
+
+// String implements the flag.Value interface, returning `"YYYYMM"` with leading zeros,
+// or the empty string if the YMFlag is nil.
+func (ym YMFlag) String() string {
+	if ym.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%06d", ym.GetYM())
+}
+
+// Set implements the flag.Value interface, parsing a 6-digit `YYYYMM`.
+// The default value of empty string `""` implies it is unset, meaning the current month.
+// Surrounding whitespace is trimmed.
+func (ym *YMFlag) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		ym.ymd.yyyymmdd = 0
+		return nil
+	}
+	if len(value) != 6 || !isInt(value) {
+		return fmt.Errorf("expect string of format YYYYMM: %w", ErrBadFormat)
+	}
+	yyyymm, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("failed to convert string %w: %w", err, ErrBadFormat)
+	}
+	if err := ValidateYM(yyyymm); err != nil {
+		return fmt.Errorf("failed to validate string %w", err)
+	}
+	ym.ymd.yyyymmdd = 100*yyyymm + 1
+	return nil
+}
+
+// GetYM returns the YMFlag as integer `YYYYMM`.  It may be zero.
+func (ym YMFlag) GetYM() int {
+	return ym.ymd.yyyymmdd / 100
+}
+
+// IsZero returns true if the YMFlag is nil.  The location is ignored in this case.
+func (ym YMFlag) IsZero() bool {
+	return ym.ymd.IsZero()
+}
+
+// Location returns the location of the YMFlag.  A nil location means local time.
+func (ym YMFlag) Location() *time.Location {
+	return ym.ymd.Location()
+}
+
+// SetLocation sets the location of the YMFlag.  A nil location means local time.
+func (ym *YMFlag) SetLocation(loc *time.Location) {
+	ym.ymd.SetLocation(loc)
+}
+
+// AsYMDFlag returns the first day of the YMFlag's month as a YMDFlag with the same location.
+// A nil YMFlag is resolved to the current month in its location, without mutating the receiver.
+func (ym YMFlag) AsYMDFlag() YMDFlag {
+	return ym.ymd.MonthStart()
+}
+
+// AsTime returns midnight on the first day of the YMFlag's month, in its location or local time if that is nil.
+// A nil YMFlag is resolved to the current month in its location, without mutating the receiver.
+func (ym YMFlag) AsTime() time.Time {
+	return ym.AsYMDFlag().AsStartOfDayTime()
+}
@@ -0,0 +1,68 @@
+package ymdflag
+
+// Copyright (c) 2023 Neomantra BV
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateYM(t *testing.T) {
+	for _, yyyymm := range []int{0, 202301, 202312, 999912} {
+		assert.NoError(t, ValidateYM(yyyymm), "%d", yyyymm)
+	}
+	for _, yyyymm := range []int{202300, 202313, -1, 1234567, 20230704} {
+		assert.True(t, errors.Is(ValidateYM(yyyymm), ErrOutOfRange), "%d", yyyymm)
+	}
+}
+
+func TestYMFlag(t *testing.T) {
+	var ym YMFlag
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.VarP(&ym, "month", "m", "YYYYMM month")
+	assert.NoError(t, fs.Parse([]string{"--month", "202307"}))
+	assert.Equal(t, 202307, ym.GetYM())
+	assert.Equal(t, "202307", ym.String())
+	assert.Equal(t, "YMFlag", ym.Type())
+
+	assert.NoError(t, ym.Set(" 202402 "))
+	assert.Equal(t, 20240201, ym.AsYMDFlag().GetYMD())
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.Local), ym.AsTime(), "AsTime is the first of the month")
+
+	for _, value := range []string{"202313", "202300"} {
+		assert.True(t, errors.Is(ym.Set(value), ErrOutOfRange), value)
+	}
+	for _, value := range []string{"20230704", "2023-07", "23007", "July"} {
+		assert.True(t, errors.Is(ym.Set(value), ErrBadFormat), value)
+	}
+	assert.Equal(t, 202402, ym.GetYM(), "unchanged on error")
+
+	assert.NoError(t, ym.Set(""))
+	assert.True(t, ym.IsZero())
+	assert.Equal(t, "", ym.String())
+}
+
+func TestYMFlagLocation(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	ym, err := NewYMFlagFromInt(202307)
+	assert.NoError(t, err)
+	ym.SetLocation(tokyo)
+	assert.Equal(t, tokyo, ym.Location())
+	assert.Equal(t, time.Date(2023, time.July, 1, 0, 0, 0, 0, tokyo), ym.AsTime())
+	assert.Equal(t, tokyo, ym.AsYMDFlag().Location())
+
+	_, err = NewYMFlagFromInt(202313)
+	assert.Error(t, err)
+
+	// 2023-07-31 22:00 UTC is already August in Tokyo
+	setNow(t, time.Date(2023, time.July, 31, 22, 0, 0, 0, time.UTC))
+	zero, err := NewYMFlagFromInt(0)
+	assert.NoError(t, err)
+	zero.SetLocation(tokyo)
+	assert.Equal(t, time.Date(2023, time.August, 1, 0, 0, 0, 0, tokyo), zero.AsTime(), "nil is the current month in its location")
+	assert.True(t, zero.IsZero(), "not mutated")
+}